@@ -0,0 +1,65 @@
+package main
+
+import "strings"
+
+// embedBitcodeFlag requests bitcode embedding, used on Android's
+// LTO/bitcode build path.
+const embedBitcodeFlag = "-fembed-bitcode"
+
+// bitcodeIncompatibleFlags are flags other wrapper steps may inject that
+// conflict with -fembed-bitcode on Android: bitcode builds ship IR rather
+// than final native code, so a recorded optimization log or split debug
+// info keyed to this compile's own codegen doesn't carry over meaningfully.
+var bitcodeIncompatibleFlags = map[string]bool{
+	"-fsave-optimization-record": true,
+	"-gsplit-dwarf":              true,
+}
+
+// isAndroidTriple reports whether triple targets Android, as opposed to
+// CrOS's own Linux/gnueabi triples.
+func isAndroidTriple(triple string) bool {
+	return strings.Contains(triple, "android")
+}
+
+// hasEmbedBitcodeFlag reports whether compilerCmd requests bitcode
+// embedding.
+func hasEmbedBitcodeFlag(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == embedBitcodeFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// processAndroidEmbedBitcode normalizes an Android bitcode build: it
+// strips bitcodeIncompatibleFlags, and normalizes -flto=thin to -flto,
+// since bitcode embedding requires full LTO's single-module IR rather than
+// thin LTO's per-module summaries. CrOS targets (and non-bitcode Android
+// compiles) are left untouched.
+func processAndroidEmbedBitcode(cfg *config, compilerCmd *command) *command {
+	if !isAndroidTriple(cfg.Target) || !hasEmbedBitcodeFlag(compilerCmd) {
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if bitcodeIncompatibleFlags[arg] {
+			changed = true
+			continue
+		}
+		if arg == "-flto=thin" {
+			newArgs = append(newArgs, "-flto")
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func androidConfig(t *testing.T) *config {
+	t.Helper()
+	cfg, ok := getConfig("android-arm64")
+	if !ok {
+		t.Fatal("expected android-arm64 to be a known config")
+	}
+	return cfg
+}
+
+func TestProcessAndroidEmbedBitcodeStripsIncompatibleFlags(t *testing.T) {
+	cfg := androidConfig(t)
+	cmd := &command{Args: []string{"-c", "foo.c", embedBitcodeFlag, "-fsave-optimization-record", "-gsplit-dwarf"}}
+
+	got := processAndroidEmbedBitcode(cfg, cmd)
+
+	for _, a := range got.Args {
+		if bitcodeIncompatibleFlags[a] {
+			t.Errorf("expected %q to be stripped, got %v", a, got.Args)
+		}
+	}
+}
+
+func TestProcessAndroidEmbedBitcodeNormalizesThinLTO(t *testing.T) {
+	cfg := androidConfig(t)
+	cmd := &command{Args: []string{"-c", "foo.c", embedBitcodeFlag, "-flto=thin"}}
+
+	got := processAndroidEmbedBitcode(cfg, cmd)
+
+	found := false
+	for _, a := range got.Args {
+		if a == "-flto=thin" {
+			t.Error("expected -flto=thin to be normalized away")
+		}
+		if a == "-flto" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected -flto to be present, got %v", got.Args)
+	}
+}
+
+func TestProcessAndroidEmbedBitcodeNoopWithoutFlag(t *testing.T) {
+	cfg := androidConfig(t)
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processAndroidEmbedBitcode(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected no change without -fembed-bitcode")
+	}
+}
+
+func TestProcessAndroidEmbedBitcodeLeavesCrosUnaffected(t *testing.T) {
+	cfg := &config{ConfigName: "arm-embedded-hardened", Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c", embedBitcodeFlag, "-fsave-optimization-record"}}
+
+	got := processAndroidEmbedBitcode(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected CrOS targets to be left untouched")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import "strings"
+
+// resolveArchCPUConflict drops a conflicting -march when -mcpu is also
+// present, for arm targets only: passing both produces warnings and
+// sometimes hard errors there, and -mcpu is the more specific of the two.
+// x86 (and any other non-arm target) is left untouched, since the two
+// flags don't conflict there.
+func resolveArchCPUConflict(e env, isArmTarget bool, compilerCmd *command) *command {
+	if !isArmTarget {
+		return compilerCmd
+	}
+
+	hasMcpu := false
+	for _, arg := range compilerCmd.Args {
+		if arg == "-mcpu" || strings.HasPrefix(arg, "-mcpu=") {
+			hasMcpu = true
+			break
+		}
+	}
+	if !hasMcpu {
+		return compilerCmd
+	}
+
+	var dropped []string
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg == "-march" || strings.HasPrefix(arg, "-march=") {
+			dropped = append(dropped, arg)
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if len(dropped) == 0 {
+		return compilerCmd
+	}
+
+	for _, arg := range dropped {
+		logDebugf(e, "dropping %q in favor of -mcpu for an arm target", arg)
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestResolveArchCPUConflictDropsMarchForArm(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-march=armv7-a", "-mcpu=cortex-a53"}}
+
+	got := resolveArchCPUConflict(e, true, cmd)
+	want := []string{"-c", "foo.c", "-mcpu=cortex-a53"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestResolveArchCPUConflictLeavesX86Untouched(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-march=x86-64", "-mcpu=cortex-a53"}}
+	got := resolveArchCPUConflict(newFakeEnv(map[string]string{}), false, cmd)
+	if got != cmd {
+		t.Errorf("expected a non-arm target to be left untouched, got %v", got.Args)
+	}
+}
+
+func TestResolveArchCPUConflictNoopWithoutMcpu(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-march=armv7-a"}}
+	got := resolveArchCPUConflict(newFakeEnv(map[string]string{}), true, cmd)
+	if got != cmd {
+		t.Errorf("expected no change without -mcpu, got %v", got.Args)
+	}
+}
+
+func TestResolveArchCPUConflictLogsUnderDebug(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	cmd := &command{Args: []string{"-march=armv7-a", "-mcpu=cortex-a53"}}
+
+	resolveArchCPUConflict(e, true, cmd)
+
+	if got := e.stderrBuf.String(); got == "" {
+		t.Error("expected a debug log line about the dropped flag")
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+// artifactsDirEnvVar, when set, overrides cfg.ArtifactsDir for every feature
+// that writes side-output artifacts (opt records, warnings reports, saved
+// invocations), so an operator can redirect them for a single debugging
+// invocation without rebuilding the wrapper.
+const artifactsDirEnvVar = "COMPILER_WRAPPER_ARTIFACTS_DIR"
+
+// printArtifactsDirFlag is a synthetic flag that prints the resolved
+// artifacts dir and exits, to help operators debug crash/warnings-dir
+// placement on a deployed wrapper.
+const printArtifactsDirFlag = "--print-artifacts-dir"
+
+// compilerArtifactsDir resolves the directory side-output artifacts should
+// be written under: artifactsDirEnvVar always wins when set, falling back
+// to cfg.ArtifactsDir otherwise. fromEnv reports which one was used.
+func compilerArtifactsDir(e env, cfg *config) (dir string, fromEnv bool) {
+	if v, ok := e.getenv(artifactsDirEnvVar); ok && v != "" {
+		return v, true
+	}
+	return cfg.ArtifactsDir, false
+}
+
+// isPrintArtifactsDirCommand reports whether compilerCmd asked to print the
+// resolved artifacts dir rather than compile.
+func isPrintArtifactsDirCommand(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == printArtifactsDirFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// runPrintArtifactsDir renders the --print-artifacts-dir output: the
+// resolved dir, and whether it came from the environment override.
+func runPrintArtifactsDir(e env, cfg *config) string {
+	dir, fromEnv := compilerArtifactsDir(e, cfg)
+	if fromEnv {
+		return dir + " (from " + artifactsDirEnvVar + ")\n"
+	}
+	return dir + "\n"
+}
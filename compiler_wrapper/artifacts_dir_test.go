@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+func TestCompilerArtifactsDirFallsBackToConfig(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{ArtifactsDir: "/from-config"}
+
+	dir, fromEnv := compilerArtifactsDir(e, cfg)
+	if dir != "/from-config" || fromEnv {
+		t.Errorf("got (%q, %v), want (\"/from-config\", false)", dir, fromEnv)
+	}
+}
+
+func TestCompilerArtifactsDirEnvOverridesConfig(t *testing.T) {
+	e := newFakeEnv(map[string]string{artifactsDirEnvVar: "/from-env"})
+	cfg := &config{ArtifactsDir: "/from-config"}
+
+	dir, fromEnv := compilerArtifactsDir(e, cfg)
+	if dir != "/from-env" || !fromEnv {
+		t.Errorf("got (%q, %v), want (\"/from-env\", true)", dir, fromEnv)
+	}
+}
+
+func TestRunPrintArtifactsDir(t *testing.T) {
+	cfg := &config{ArtifactsDir: "/from-config"}
+
+	got := runPrintArtifactsDir(newFakeEnv(map[string]string{}), cfg)
+	if got != "/from-config\n" {
+		t.Errorf("got %q, want \"/from-config\\n\"", got)
+	}
+
+	gotEnv := runPrintArtifactsDir(newFakeEnv(map[string]string{artifactsDirEnvVar: "/from-env"}), cfg)
+	if gotEnv != "/from-env (from "+artifactsDirEnvVar+")\n" {
+		t.Errorf("got %q", gotEnv)
+	}
+}
+
+func TestIsPrintArtifactsDirCommand(t *testing.T) {
+	if !isPrintArtifactsDirCommand(&command{Args: []string{"--print-artifacts-dir"}}) {
+		t.Error("expected --print-artifacts-dir to be recognized")
+	}
+	if isPrintArtifactsDirCommand(&command{Args: []string{"-c", "foo.c"}}) {
+		t.Error("expected an ordinary compile not to be recognized")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// bisectConfigFileEnvVar names a JSON file supplying the bisect stage/dir
+// (and optional extra args) for build contexts where BISECT_STAGE/
+// BISECT_DIR can't be set as plain environment variables. BISECT_STAGE and
+// BISECT_DIR still take precedence when set, so existing invocations that
+// already export them see no change.
+const bisectConfigFileEnvVar = "BISECT_CONFIG_FILE"
+
+// bisectFileConfig is the shape of the JSON file named by
+// BISECT_CONFIG_FILE.
+type bisectFileConfig struct {
+	Stage string   `json:"stage"`
+	Dir   string   `json:"dir"`
+	Args  []string `json:"args"`
+}
+
+// loadBisectFileConfig reads and parses the file named by
+// BISECT_CONFIG_FILE, if set. ok is false if the var is unset, the file
+// can't be read, or it doesn't parse as JSON.
+func loadBisectFileConfig(e env) (cfg bisectFileConfig, ok bool) {
+	path, isSet := e.getenv(bisectConfigFileEnvVar)
+	if !isSet || path == "" {
+		return bisectFileConfig{}, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return bisectFileConfig{}, false
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return bisectFileConfig{}, false
+	}
+	return cfg, true
+}
+
+// getBisectStage resolves the bisect stage to run: BISECT_STAGE if set,
+// otherwise the "stage" field from BISECT_CONFIG_FILE, otherwise "".
+func getBisectStage(e env) string {
+	if v, ok := e.getenv("BISECT_STAGE"); ok && v != "" {
+		return v
+	}
+	if cfg, ok := loadBisectFileConfig(e); ok {
+		return cfg.Stage
+	}
+	return ""
+}
+
+// getBisectDir resolves the bisect working directory the same way
+// getBisectStage resolves the stage: BISECT_DIR if set, otherwise the
+// "dir" field from BISECT_CONFIG_FILE, otherwise "".
+func getBisectDir(e env) string {
+	if v, ok := e.getenv("BISECT_DIR"); ok && v != "" {
+		return v
+	}
+	if cfg, ok := loadBisectFileConfig(e); ok {
+		return cfg.Dir
+	}
+	return ""
+}
+
+// calcBisectCommand builds the bisect driver invocation for compilerCmd:
+// the resolved stage and dir as leading flags, any extra args from
+// BISECT_CONFIG_FILE's "args" field, followed by the original command's
+// own args. It returns ok=false if no stage is configured at all, meaning
+// bisection isn't active for this invocation.
+func calcBisectCommand(e env, compilerCmd *command) (cmd *command, ok bool) {
+	stage := getBisectStage(e)
+	if stage == "" {
+		return nil, false
+	}
+	dir := getBisectDir(e)
+
+	args := []string{"-stage", stage}
+	if dir != "" {
+		args = append(args, "-dir", dir)
+	}
+	if fileCfg, fromFile := loadBisectFileConfig(e); fromFile {
+		args = append(args, fileCfg.Args...)
+	}
+	args = append(args, compilerCmd.Args...)
+
+	newCmd := *compilerCmd
+	newCmd.Args = args
+	return &newCmd, true
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeBisectConfigFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "bisect.json")
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatalf("writing bisect config file: %v", err)
+	}
+	return path
+}
+
+func TestGetBisectStageFromFile(t *testing.T) {
+	path := writeBisectConfigFile(t, `{"stage": "8", "dir": "/tmp/bisect"}`)
+	e := newFakeEnv(map[string]string{bisectConfigFileEnvVar: path})
+
+	if got := getBisectStage(e); got != "8" {
+		t.Errorf("got %q, want %q", got, "8")
+	}
+	if got := getBisectDir(e); got != "/tmp/bisect" {
+		t.Errorf("got %q, want %q", got, "/tmp/bisect")
+	}
+}
+
+func TestGetBisectStageEnvOverridesFile(t *testing.T) {
+	path := writeBisectConfigFile(t, `{"stage": "8", "dir": "/tmp/bisect"}`)
+	e := newFakeEnv(map[string]string{
+		bisectConfigFileEnvVar: path,
+		"BISECT_STAGE":         "3",
+		"BISECT_DIR":           "/tmp/env-bisect",
+	})
+
+	if got := getBisectStage(e); got != "3" {
+		t.Errorf("got %q, want env override %q", got, "3")
+	}
+	if got := getBisectDir(e); got != "/tmp/env-bisect" {
+		t.Errorf("got %q, want env override %q", got, "/tmp/env-bisect")
+	}
+}
+
+func TestGetBisectStageUnconfigured(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := getBisectStage(e); got != "" {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestCalcBisectCommandFromFile(t *testing.T) {
+	path := writeBisectConfigFile(t, `{"stage": "8", "dir": "/tmp/bisect", "args": ["-v"]}`)
+	e := newFakeEnv(map[string]string{bisectConfigFileEnvVar: path})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got, ok := calcBisectCommand(e, cmd)
+	if !ok {
+		t.Fatal("expected bisection to be active")
+	}
+	want := []string{"-stage", "8", "-dir", "/tmp/bisect", "-v", "-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestCalcBisectCommandInactiveWithoutStage(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	if _, ok := calcBisectCommand(e, cmd); ok {
+		t.Error("expected bisection to be inactive without a configured stage")
+	}
+}
+
+func TestLoadBisectFileConfigInvalidJSONFallsBackToUnset(t *testing.T) {
+	path := writeBisectConfigFile(t, `not valid json`)
+	e := newFakeEnv(map[string]string{bisectConfigFileEnvVar: path})
+
+	if _, ok := loadBisectFileConfig(e); ok {
+		t.Error("expected invalid JSON to report ok=false")
+	}
+}
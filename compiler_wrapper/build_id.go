@@ -0,0 +1,23 @@
+package main
+
+import "strings"
+
+// processInjectBuildID adds -Wl,--build-id=sha1 to link commands for
+// configs that opt in via config.InjectBuildID, so every resulting binary
+// carries a build-id usable for crash symbolication. It does nothing for
+// non-link invocations, or if the user already specified a --build-id of
+// their own.
+func processInjectBuildID(cfg *config, compilerCmd *command) *command {
+	if !cfg.InjectBuildID || !isLinkOnly(compilerCmd) {
+		return compilerCmd
+	}
+	for _, arg := range compilerCmd.Args {
+		if strings.Contains(arg, "--build-id") {
+			return compilerCmd
+		}
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-Wl,--build-id=sha1")
+	return &newCmd
+}
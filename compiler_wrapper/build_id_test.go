@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestProcessInjectBuildIDInjectsOnLinkCommand(t *testing.T) {
+	cfg := &config{InjectBuildID: true}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o"}}
+
+	got := processInjectBuildID(cfg, cmd)
+
+	found := false
+	for _, a := range got.Args {
+		if a == "-Wl,--build-id=sha1" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected -Wl,--build-id=sha1 to be injected, got %v", got.Args)
+	}
+}
+
+func TestProcessInjectBuildIDSuppressedByUserFlag(t *testing.T) {
+	cfg := &config{InjectBuildID: true}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o", "-Wl,--build-id=md5"}}
+
+	got := processInjectBuildID(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected a user-supplied --build-id to suppress injection")
+	}
+}
+
+func TestProcessInjectBuildIDSkippedForCompileStep(t *testing.T) {
+	cfg := &config{InjectBuildID: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processInjectBuildID(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected a compile-only command to be left untouched")
+	}
+}
+
+func TestProcessInjectBuildIDOffByDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o"}}
+
+	got := processInjectBuildID(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected no injection when InjectBuildID is unset")
+	}
+}
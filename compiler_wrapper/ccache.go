@@ -0,0 +1,104 @@
+package main
+
+import (
+	"os"
+	"strings"
+)
+
+// noCCacheMarkerFlag is a synthetic flag (stripped from the final command,
+// same convention as noPieMarkerFlag) that callers pass to force ccache off
+// for one invocation regardless of the board's configured default.
+const noCCacheMarkerFlag = "-noccache"
+
+// featuresDisablesCCache reports whether Portage's FEATURES env var
+// contains the "-ccache" token, which is FEATURES' own way of asking for
+// ccache to be off. FEATURES is a space-separated list of tokens, some
+// negated with a leading '-'.
+func featuresDisablesCCache(e env) bool {
+	features, ok := e.getenv("FEATURES")
+	if !ok {
+		return false
+	}
+	for _, tok := range strings.Fields(features) {
+		if tok == "-ccache" {
+			return true
+		}
+	}
+	return false
+}
+
+// forceCCacheEnabled reports whether COMPILER_WRAPPER_FORCE_CCACHE is set,
+// overriding every other signal to turn ccache on.
+func forceCCacheEnabled(e env) bool {
+	_, ok := e.getenv("COMPILER_WRAPPER_FORCE_CCACHE")
+	return ok
+}
+
+// gomaStatFunc stats a candidate gomacc path, overridable in tests so they
+// don't depend on any real binary existing on disk.
+var gomaStatFunc = os.Stat
+
+// gomaPresent reports whether GOMACC_PATH is set and points at a file that
+// exists, meaning goma should handle this compile instead of ccache.
+func gomaPresent(e env) bool {
+	path, ok := e.getenv("GOMACC_PATH")
+	if !ok || path == "" {
+		return false
+	}
+	info, err := gomaStatFunc(path)
+	return err == nil && !info.IsDir()
+}
+
+// shouldUseCCache decides whether ccache should wrap this compile, given
+// the board's configured default (useCCache) and compilerCmd. It's a thin
+// wrapper around ccacheDecision for callers that don't need the reason.
+func shouldUseCCache(e env, useCCache bool, compilerCmd *command) bool {
+	use, _ := ccacheDecision(e, useCCache, compilerCmd)
+	return use
+}
+
+// ccacheDecision decides whether ccache should wrap this compile, given the
+// board's configured default (useCCache) and compilerCmd, and explains why
+// in a short human-readable reason. Precedence, highest first:
+//  1. -noccache on the command line always wins: off.
+//  2. COMPILER_WRAPPER_FORCE_CCACHE always wins: on.
+//  3. A present gomacc takes the compile instead: off.
+//  4. FEATURES containing "-ccache" turns it off even if useCCache is true.
+//  5. Otherwise, the board's configured default.
+func ccacheDecision(e env, useCCache bool, compilerCmd *command) (use bool, reason string) {
+	for _, arg := range compilerCmd.Args {
+		if arg == noCCacheMarkerFlag {
+			return false, "-noccache was passed on the command line"
+		}
+	}
+	if forceCCacheEnabled(e) {
+		return true, "COMPILER_WRAPPER_FORCE_CCACHE is set"
+	}
+	if gomaPresent(e) {
+		return false, "gomacc is present; goma handles this compile instead"
+	}
+	if featuresDisablesCCache(e) {
+		return false, "FEATURES contains -ccache"
+	}
+	if useCCache {
+		return true, "useCCache is enabled for this config"
+	}
+	return false, "useCCache is disabled for this config"
+}
+
+// ccacheBinary is the ccache executable name. Unlike gomacc, whose path
+// varies per sysroot and must be pointed at explicitly via GOMACC_PATH,
+// ccache is expected to be resolved off PATH like any other bare command
+// name.
+const ccacheBinary = "ccache"
+
+// wrapWithCCache returns a copy of compilerCmd that runs it through ccache:
+// "ccache <original path> <original args>", so the single command
+// execCompiler execs is ccache itself, which in turn invokes the real
+// compiler on a cache miss.
+func wrapWithCCache(compilerCmd *command) *command {
+	newCmd := *compilerCmd
+	newCmd.Args = append([]string{compilerCmd.Path}, compilerCmd.Args...)
+	newCmd.Path = ccacheBinary
+	return &newCmd
+}
@@ -0,0 +1,26 @@
+package main
+
+import "fmt"
+
+// ccacheExplainEnabled reports whether COMPILER_WRAPPER_CCACHE_EXPLAIN=1 is
+// set, opting into printing the ccache decision and its reason to stderr.
+func ccacheExplainEnabled(e env) bool {
+	v, ok := e.getenv("COMPILER_WRAPPER_CCACHE_EXPLAIN")
+	return ok && v == "1"
+}
+
+// explainCCacheDecision prints use and reason to e's stderr, read-only and
+// side-effect free otherwise, when ccacheExplainEnabled(e) is true.
+func explainCCacheDecision(e env, use bool, reason string) {
+	if !ccacheExplainEnabled(e) {
+		return
+	}
+	fmt.Fprintf(e.stderr(), "ccache: %s (%s)\n", decisionWord(use), reason)
+}
+
+func decisionWord(use bool) string {
+	if use {
+		return "enabled"
+	}
+	return "disabled"
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestCCacheDecisionReasons(t *testing.T) {
+	cases := []struct {
+		name       string
+		env        map[string]string
+		useCCache  bool
+		args       []string
+		wantUse    bool
+		wantReason string
+	}{
+		{
+			name:       "noccache flag",
+			args:       []string{"-noccache"},
+			useCCache:  true,
+			wantUse:    false,
+			wantReason: "-noccache was passed on the command line",
+		},
+		{
+			name:       "force override",
+			env:        map[string]string{"COMPILER_WRAPPER_FORCE_CCACHE": "1"},
+			useCCache:  false,
+			wantUse:    true,
+			wantReason: "COMPILER_WRAPPER_FORCE_CCACHE is set",
+		},
+		{
+			name:       "features disables",
+			env:        map[string]string{"FEATURES": "-ccache"},
+			useCCache:  true,
+			wantUse:    false,
+			wantReason: "FEATURES contains -ccache",
+		},
+		{
+			name:       "configured on",
+			useCCache:  true,
+			wantUse:    true,
+			wantReason: "useCCache is enabled for this config",
+		},
+		{
+			name:       "configured off",
+			useCCache:  false,
+			wantUse:    false,
+			wantReason: "useCCache is disabled for this config",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newFakeEnv(tc.env)
+			use, reason := ccacheDecision(e, tc.useCCache, &command{Args: tc.args})
+			if use != tc.wantUse || reason != tc.wantReason {
+				t.Errorf("got (%v, %q), want (%v, %q)", use, reason, tc.wantUse, tc.wantReason)
+			}
+		})
+	}
+}
+
+// fakeRegularFileInfo is a minimal os.FileInfo stand-in for a regular file,
+// used to fake gomaStatFunc without touching the real filesystem.
+type fakeRegularFileInfo struct{ os.FileInfo }
+
+func (fakeRegularFileInfo) IsDir() bool { return false }
+
+func TestCCacheDecisionGomaPresent(t *testing.T) {
+	orig := gomaStatFunc
+	defer func() { gomaStatFunc = orig }()
+	gomaStatFunc = func(name string) (os.FileInfo, error) {
+		return fakeRegularFileInfo{}, nil
+	}
+
+	e := newFakeEnv(map[string]string{"GOMACC_PATH": "/fake/gomacc"})
+	use, reason := ccacheDecision(e, true, &command{})
+	if use || reason != "gomacc is present; goma handles this compile instead" {
+		t.Errorf("got (%v, %q)", use, reason)
+	}
+}
+
+func TestExplainCCacheDecisionPrintsWhenEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_CCACHE_EXPLAIN": "1"})
+	explainCCacheDecision(e, false, "FEATURES contains -ccache")
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "disabled") || !strings.Contains(got, "FEATURES contains -ccache") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestExplainCCacheDecisionSilentByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	explainCCacheDecision(e, true, "useCCache is enabled for this config")
+
+	if got := e.stderrBuf.String(); got != "" {
+		t.Errorf("expected no output without COMPILER_WRAPPER_CCACHE_EXPLAIN, got %q", got)
+	}
+}
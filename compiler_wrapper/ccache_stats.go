@@ -0,0 +1,25 @@
+package main
+
+// ccacheStatsFileEnvVar names a file that ccache should append its
+// per-compile stats log to, for cache-efficiency dashboards that want a
+// durable record of hits/misses rather than polling `ccache -s`.
+const ccacheStatsFileEnvVar = "COMPILER_WRAPPER_CCACHE_STATS_FILE"
+
+// processCCacheStats injects CCACHE_STATSLOG=<path> into compilerCmd's
+// EnvUpdates when COMPILER_WRAPPER_CCACHE_STATS_FILE is set and ccache is
+// actually wrapping this compile (useCCache), so ccache appends its stats
+// log there. It's a no-op when the env var is unset or ccache isn't in use.
+func processCCacheStats(e env, useCCache bool, compilerCmd *command) *command {
+	path, ok := e.getenv(ccacheStatsFileEnvVar)
+	if !ok || path == "" || !useCCache {
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.EnvUpdates = make(map[string]string, len(compilerCmd.EnvUpdates)+1)
+	for k, v := range compilerCmd.EnvUpdates {
+		newCmd.EnvUpdates[k] = v
+	}
+	newCmd.EnvUpdates["CCACHE_STATSLOG"] = path
+	return &newCmd
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestProcessCCacheStatsInjectsStatsLog(t *testing.T) {
+	e := newFakeEnv(map[string]string{ccacheStatsFileEnvVar: "/tmp/ccache-stats.log"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processCCacheStats(e, true, cmd)
+
+	if got.EnvUpdates["CCACHE_STATSLOG"] != "/tmp/ccache-stats.log" {
+		t.Errorf("got %v, want CCACHE_STATSLOG=/tmp/ccache-stats.log", got.EnvUpdates)
+	}
+}
+
+func TestProcessCCacheStatsNoopWhenCCacheUnused(t *testing.T) {
+	e := newFakeEnv(map[string]string{ccacheStatsFileEnvVar: "/tmp/ccache-stats.log"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processCCacheStats(e, false, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when ccache isn't in use")
+	}
+}
+
+func TestProcessCCacheStatsNoopWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processCCacheStats(e, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when COMPILER_WRAPPER_CCACHE_STATS_FILE is unset")
+	}
+}
@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestFeaturesDisablesCCache(t *testing.T) {
+	if !featuresDisablesCCache(newFakeEnv(map[string]string{"FEATURES": "noman -ccache userpriv"})) {
+		t.Error("expected -ccache in FEATURES to disable ccache")
+	}
+	if featuresDisablesCCache(newFakeEnv(map[string]string{"FEATURES": "noman userpriv"})) {
+		t.Error("expected ccache not to be disabled without -ccache in FEATURES")
+	}
+	if featuresDisablesCCache(newFakeEnv(map[string]string{})) {
+		t.Error("expected no FEATURES to leave ccache alone")
+	}
+}
+
+func TestShouldUseCCacheFeaturesDisable(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FEATURES": "-ccache"})
+	if shouldUseCCache(e, true, &command{}) {
+		t.Error("expected FEATURES=-ccache to disable ccache even though useCCache is true")
+	}
+}
+
+func TestShouldUseCCacheForceOverridesFeatures(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FEATURES": "-ccache", "COMPILER_WRAPPER_FORCE_CCACHE": "1"})
+	if !shouldUseCCache(e, false, &command{}) {
+		t.Error("expected COMPILER_WRAPPER_FORCE_CCACHE to win over FEATURES and the configured default")
+	}
+}
+
+func TestShouldUseCCacheNoCCacheFlagWinsOverForce(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_FORCE_CCACHE": "1"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-noccache"}}
+	if shouldUseCCache(e, true, cmd) {
+		t.Error("expected -noccache on the command line to win over every other signal")
+	}
+}
+
+func TestShouldUseCCacheDefaultsToConfiguredValue(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if shouldUseCCache(e, false, &command{}) {
+		t.Error("expected useCCache=false to be honored absent any override")
+	}
+	if !shouldUseCCache(e, true, &command{}) {
+		t.Error("expected useCCache=true to be honored absent any override")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// checkGoldensFlag is the synthetic driver flag that, instead of compiling,
+// replays a directory of golden records against the live config and
+// reports any mismatches, so a built binary can be validated against
+// committed goldens without the Go test harness.
+const checkGoldensFlag = "--wrapper-check-goldens"
+
+// isCheckGoldensCommand reports whether compilerCmd asked to validate a
+// golden directory, and if so, which one.
+func isCheckGoldensCommand(compilerCmd *command) (dir string, ok bool) {
+	for i, arg := range compilerCmd.Args {
+		if arg == checkGoldensFlag && i+1 < len(compilerCmd.Args) {
+			return compilerCmd.Args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// goldenRecord is one committed example: a target and an input argv,
+// together with the injected flags the wrapper produced for it when the
+// record was captured.
+type goldenRecord struct {
+	Target        string   `json:"target"`
+	Args          []string `json:"args"`
+	InjectedFlags []string `json:"injected_flags"`
+}
+
+// loadGoldenRecords reads every "*.json" file in dir as a goldenRecord,
+// sorted by filename for deterministic, reproducible diff ordering.
+func loadGoldenRecords(dir string) ([]goldenRecord, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	records := make([]goldenRecord, 0, len(names))
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, fmt.Errorf("reading golden %s: %w", name, err)
+		}
+		var rec goldenRecord
+		if err := json.Unmarshal(data, &rec); err != nil {
+			return nil, fmt.Errorf("parsing golden %s: %w", name, err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// goldenDiff describes one golden record whose current behavior no longer
+// matches what was committed.
+type goldenDiff struct {
+	Args string
+	Want []string
+	Got  []string
+}
+
+// checkGoldens replays each record in dir through injectedFlags and
+// reports any whose current result differs from the committed
+// InjectedFlags.
+func checkGoldens(dir string) (diffs []goldenDiff, err error) {
+	records, err := loadGoldenRecords(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, rec := range records {
+		cfg := &config{Target: rec.Target}
+		got := injectedFlags(cfg, &command{Args: rec.Args})
+		if !flagsEqual(got, rec.InjectedFlags) {
+			diffs = append(diffs, goldenDiff{
+				Args: strings.Join(rec.Args, " "),
+				Want: rec.InjectedFlags,
+				Got:  got,
+			})
+		}
+	}
+	return diffs, nil
+}
+
+func flagsEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// runCheckGoldens renders checkGoldens' result as a human-readable report,
+// returning a non-empty report only when at least one mismatch was found.
+func runCheckGoldens(dir string) (report string, ok bool) {
+	diffs, err := checkGoldens(dir)
+	if err != nil {
+		return fmt.Sprintf("check-goldens: %v\n", err), false
+	}
+	if len(diffs) == 0 {
+		return "", true
+	}
+
+	var b strings.Builder
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "MISMATCH %s\n  want: %v\n  got:  %v\n", d.Args, d.Want, d.Got)
+	}
+	return b.String(), false
+}
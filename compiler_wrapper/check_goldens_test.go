@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeGolden(t *testing.T, dir, name string, rec goldenRecord) {
+	t.Helper()
+	data, err := json.Marshal(rec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsCheckGoldensCommand(t *testing.T) {
+	cmd := &command{Args: []string{checkGoldensFlag, "/path/to/goldens"}}
+	dir, ok := isCheckGoldensCommand(cmd)
+	if !ok || dir != "/path/to/goldens" {
+		t.Errorf("got (%q, %v), want (/path/to/goldens, true)", dir, ok)
+	}
+}
+
+func TestRunCheckGoldensMatchingDirReportsNoDiffs(t *testing.T) {
+	dir := t.TempDir()
+	writeGolden(t, dir, "sample.json", goldenRecord{
+		Target:        "armv7a-cros-linux-gnu",
+		Args:          []string{"-c", "foo.c"},
+		InjectedFlags: []string{"-target", "armv7a-cros-linux-gnu", "-fPIE", "-pie"},
+	})
+
+	report, ok := runCheckGoldens(dir)
+	if !ok {
+		t.Errorf("expected a matching golden dir to pass, got report:\n%s", report)
+	}
+	if report != "" {
+		t.Errorf("expected an empty report on success, got %q", report)
+	}
+}
+
+func TestRunCheckGoldensTamperedDirReportsDiff(t *testing.T) {
+	dir := t.TempDir()
+	writeGolden(t, dir, "sample.json", goldenRecord{
+		Target:        "armv7a-cros-linux-gnu",
+		Args:          []string{"-c", "foo.c"},
+		InjectedFlags: []string{"-target", "armv7a-cros-linux-gnu"},
+	})
+
+	report, ok := runCheckGoldens(dir)
+	if ok {
+		t.Fatal("expected a tampered golden to fail")
+	}
+	if report == "" {
+		t.Error("expected a non-empty mismatch report")
+	}
+}
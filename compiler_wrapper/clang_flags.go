@@ -0,0 +1,105 @@
+package main
+
+import "strings"
+
+// hasUserTargetFlag reports whether compilerCmd already carries a
+// user-specified --target=/-target flag, or -m32, any of which determine
+// the effective target on their own.
+func hasUserTargetFlag(compilerCmd *command) bool {
+	for i, arg := range compilerCmd.Args {
+		switch {
+		case arg == "-target":
+			return i+1 < len(compilerCmd.Args)
+		case strings.HasPrefix(arg, "--target="):
+			return true
+		case arg == "-m32":
+			return true
+		}
+	}
+	return false
+}
+
+// processClangFlags appends the derived -target <triple> needed to steer
+// clang (which, unlike gcc, isn't built for a single fixed target) at the
+// board's architecture, unless the user already passed their own
+// --target=/-target/-m32, in which case theirs wins and nothing is
+// injected.
+func processClangFlags(triple string, compilerCmd *command) *command {
+	if hasUserTargetFlag(compilerCmd) {
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-target", triple)
+	return &newCmd
+}
+
+// xclangOnlyPrefix marks an argument meant for clang alone. gcc has no
+// equivalent and must never see the raw flag, so each occurrence is either
+// unwrapped to its bare form (clang) or dropped outright (gcc).
+const xclangOnlyPrefix = "-Xclang-only="
+
+// processXclangOnlyFlags independently unwraps every -Xclang-only=<flag>
+// into <flag> for clang, preserving its position relative to the
+// surrounding args, and drops every occurrence outright for gcc, which
+// doesn't understand the prefix at all.
+func processXclangOnlyFlags(compilerIsClang bool, compilerCmd *command) *command {
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, xclangOnlyPrefix) {
+			changed = true
+			if compilerIsClang {
+				newArgs = append(newArgs, strings.TrimPrefix(arg, xclangOnlyPrefix))
+			}
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// clangDriverPath resolves which clang binary to invoke: CLANGXX when
+// isCxxDriver and set (for toolchain installs that ship a separate clang++
+// binary or wrapper script), otherwise CLANG, falling back to derived (the
+// path the wrapper would otherwise compute on its own) when neither
+// environment override is set.
+func clangDriverPath(e env, isCxxDriver bool, derived string) string {
+	if isCxxDriver {
+		if v, ok := e.getenv("CLANGXX"); ok && v != "" {
+			return v
+		}
+	}
+	if v, ok := e.getenv("CLANG"); ok && v != "" {
+		return v
+	}
+	return derived
+}
+
+// hasBFlag reports whether compilerCmd already carries the exact -B<path>
+// argument.
+func hasBFlag(compilerCmd *command, path string) bool {
+	flag := "-B" + path
+	for _, arg := range compilerCmd.Args {
+		if arg == flag {
+			return true
+		}
+	}
+	return false
+}
+
+// processClangLinkerPath appends -B<cfg.LinkerPath>, pointing clang at the
+// board's chosen linker, unless the user already passed that exact -B flag
+// themselves, in which case duplicating it would be harmless to clang but
+// still noise on the command line.
+func processClangLinkerPath(cfg *config, compilerCmd *command) *command {
+	if cfg.LinkerPath == "" || hasBFlag(compilerCmd, cfg.LinkerPath) {
+		return compilerCmd
+	}
+	return addPostUserArgs(compilerCmd, []string{"-B" + cfg.LinkerPath})
+}
@@ -0,0 +1,142 @@
+package main
+
+import "testing"
+
+func TestProcessClangFlagsInjectsDerivedTarget(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	got := processClangFlags("armv7a-cros-linux-gnueabihf", cmd)
+
+	want := []string{"-c", "foo.c", "-target", "armv7a-cros-linux-gnueabihf"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessClangFlagsUserTargetWins(t *testing.T) {
+	for _, args := range [][]string{
+		{"-c", "foo.c", "--target=x86_64-linux-gnu"},
+		{"-c", "foo.c", "-target", "x86_64-linux-gnu"},
+		{"-c", "foo.c", "-m32"},
+	} {
+		cmd := &command{Args: args}
+		got := processClangFlags("armv7a-cros-linux-gnueabihf", cmd)
+		if got != cmd {
+			t.Errorf("expected command to be returned unchanged for args %v, got %v", args, got.Args)
+		}
+	}
+}
+
+func TestHasUserTargetFlagDanglingMinusTarget(t *testing.T) {
+	// A trailing "-target" with no value isn't a complete user override.
+	if hasUserTargetFlag(&command{Args: []string{"-c", "foo.c", "-target"}}) {
+		t.Error("expected a dangling -target with no value not to count as a user override")
+	}
+}
+
+func TestProcessXclangOnlyFlagsUnwrapsEachForClang(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Xclang-only=-foo", "bar.c", "-Xclang-only=-baz"}}
+
+	got := processXclangOnlyFlags(true, cmd)
+
+	want := []string{"-c", "-foo", "bar.c", "-baz"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessXclangOnlyFlagsDropsEachForGcc(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Xclang-only=-foo", "bar.c", "-Xclang-only=-baz"}}
+
+	got := processXclangOnlyFlags(false, cmd)
+
+	want := []string{"-c", "bar.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessXclangOnlyFlagsNoop(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processXclangOnlyFlags(true, cmd)
+	if got != cmd {
+		t.Error("expected no change without an -Xclang-only= flag")
+	}
+}
+
+func TestProcessClangLinkerPathAppendsBFlag(t *testing.T) {
+	cfg := &config{LinkerPath: "/usr/bin/ld.lld"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processClangLinkerPath(cfg, cmd)
+
+	last := got.Args[len(got.Args)-1]
+	if last != "-B/usr/bin/ld.lld" {
+		t.Errorf("last arg = %q, want -B/usr/bin/ld.lld", last)
+	}
+}
+
+func TestProcessClangLinkerPathDedupsUserSuppliedFlag(t *testing.T) {
+	cfg := &config{LinkerPath: "/usr/bin/ld.lld"}
+	cmd := &command{Args: []string{"-c", "foo.c", "-B/usr/bin/ld.lld"}}
+
+	got := processClangLinkerPath(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when the user already passed the same -B flag")
+	}
+}
+
+func TestClangDriverPathCxxUsesClangxx(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CLANGXX": "/opt/clang++", "CLANG": "/opt/clang"})
+	if got := clangDriverPath(e, true, "/derived/clang"); got != "/opt/clang++" {
+		t.Errorf("got %q, want /opt/clang++", got)
+	}
+}
+
+func TestClangDriverPathCUsesClang(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CLANGXX": "/opt/clang++", "CLANG": "/opt/clang"})
+	if got := clangDriverPath(e, false, "/derived/clang"); got != "/opt/clang" {
+		t.Errorf("got %q, want /opt/clang", got)
+	}
+}
+
+func TestClangDriverPathCxxFallsBackToClang(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CLANG": "/opt/clang"})
+	if got := clangDriverPath(e, true, "/derived/clang"); got != "/opt/clang" {
+		t.Errorf("got %q, want /opt/clang", got)
+	}
+}
+
+func TestClangDriverPathFallsBackToDerived(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := clangDriverPath(e, true, "/derived/clang"); got != "/derived/clang" {
+		t.Errorf("got %q, want /derived/clang", got)
+	}
+}
+
+func TestProcessClangLinkerPathNoopWhenUnset(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processClangLinkerPath(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when LinkerPath is unset")
+	}
+}
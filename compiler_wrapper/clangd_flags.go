@@ -0,0 +1,66 @@
+package main
+
+import "strings"
+
+// clangdFlagsCommandFlag is a synthetic driver flag that, instead of
+// compiling, prints the flags the wrapper would inject on top of the given
+// invocation, one per line, suitable for pasting into clangd's
+// CompileFlags.Add so editor diagnostics match what a real build sees.
+const clangdFlagsCommandFlag = "--wrapper-clangd-flags"
+
+// isClangdFlagsCommand reports whether compilerCmd asked to print injected
+// flags rather than compile.
+func isClangdFlagsCommand(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == clangdFlagsCommandFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// isEabiTriple reports whether triple names an EABI target, for which
+// processPieFlags doesn't inject -fPIE -pie.
+func isEabiTriple(triple string) bool {
+	return strings.Contains(triple, "eabi")
+}
+
+// stripClangdFlagsCommandFlag returns a copy of compilerCmd with
+// clangdFlagsCommandFlag removed, so it doesn't itself show up as an
+// "injected" flag when diffed against the pipeline's output.
+func stripClangdFlagsCommandFlag(compilerCmd *command) *command {
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg != clangdFlagsCommandFlag {
+			newArgs = append(newArgs, arg)
+		}
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// injectedFlags runs compilerCmd through the subset of the flag pipeline
+// that only ever appends flags (target derivation, PIE), and returns the
+// flags that got appended. It's the same computation the real compile uses
+// to decide what to add; only the bookkeeping to report it back differs.
+func injectedFlags(cfg *config, compilerCmd *command) []string {
+	base := stripClangdFlagsCommandFlag(compilerCmd)
+	withTarget := processClangFlags(builderTarget(cfg), base)
+	withPie := processPieFlags(isEabiTriple(builderTarget(cfg)), withTarget)
+
+	if len(withPie.Args) <= len(base.Args) {
+		return nil
+	}
+	return append([]string{}, withPie.Args[len(base.Args):]...)
+}
+
+// runPrintClangdFlags renders injectedFlags one per line, as
+// clangd's CompileFlags.Add expects.
+func runPrintClangdFlags(cfg *config, compilerCmd *command) string {
+	flags := injectedFlags(cfg, compilerCmd)
+	if len(flags) == 0 {
+		return ""
+	}
+	return strings.Join(flags, "\n") + "\n"
+}
@@ -0,0 +1,66 @@
+package main
+
+import "testing"
+
+func TestIsClangdFlagsCommand(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", clangdFlagsCommandFlag}}
+	if !isClangdFlagsCommand(cmd) {
+		t.Error("expected the clangd-flags flag to be recognized")
+	}
+	if isClangdFlagsCommand(&command{Args: []string{"-c", "foo.c"}}) {
+		t.Error("expected a normal compile not to be recognized")
+	}
+}
+
+func TestInjectedFlagsListsTargetAndPie(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnu"}
+	cmd := &command{Args: []string{"-c", "foo.c", clangdFlagsCommandFlag}}
+
+	got := injectedFlags(cfg, cmd)
+	want := []string{"-target", "armv7a-cros-linux-gnu", "-fPIE", "-pie"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("flag %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInjectedFlagsOmitsPieForEabiTarget(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := injectedFlags(cfg, cmd)
+	want := []string{"-target", "armv7a-cros-linux-gnueabihf"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("flag %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestInjectedFlagsEmptyWhenUserAlreadySpecified(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnu"}
+	cmd := &command{Args: []string{"-c", "foo.c", "--target=x86_64-linux-gnu", "-fPIC"}}
+
+	got := injectedFlags(cfg, cmd)
+	if len(got) != 0 {
+		t.Errorf("expected no injected flags when the user already specified their own, got %v", got)
+	}
+}
+
+func TestRunPrintClangdFlagsOnePerLine(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnu"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := runPrintClangdFlags(cfg, cmd)
+	want := "-target\narmv7a-cros-linux-gnu\n-fPIE\n-pie\n"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
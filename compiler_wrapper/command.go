@@ -0,0 +1,10 @@
+package main
+
+// command describes a single compiler invocation: the binary to execute,
+// its arguments, and any environment variables to overlay on top of the
+// wrapper's own environment.
+type command struct {
+	Path       string
+	Args       []string
+	EnvUpdates map[string]string
+}
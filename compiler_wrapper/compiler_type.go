@@ -0,0 +1,33 @@
+package main
+
+import "strings"
+
+// forceCompilerTypeEnvVar overrides the name-based clang/gcc detection
+// below, for installs where the real compiler binary isn't named
+// conventionally (e.g. a "cc" that's actually clang).
+const forceCompilerTypeEnvVar = "COMPILER_WRAPPER_FORCE_COMPILER_TYPE"
+
+// compilerIsClang reports whether the compiler at path should be treated as
+// clang. COMPILER_WRAPPER_FORCE_COMPILER_TYPE=clang|gcc overrides the
+// name-based guess entirely; any other value (or none) falls back to
+// looking for "clang" in the binary's base name.
+func compilerIsClang(e env, path string) bool {
+	if v, ok := e.getenv(forceCompilerTypeEnvVar); ok {
+		switch v {
+		case "clang":
+			return true
+		case "gcc":
+			return false
+		}
+	}
+	return strings.Contains(lastPathComponent(path), "clang")
+}
+
+// lastPathComponent returns the final "/"-separated component of path,
+// without pulling in path/filepath for a lookup this simple.
+func lastPathComponent(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
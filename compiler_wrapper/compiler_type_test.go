@@ -0,0 +1,34 @@
+package main
+
+import "testing"
+
+func TestCompilerIsClangByName(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if !compilerIsClang(e, "/usr/bin/armv7a-cros-linux-gnueabihf-clang") {
+		t.Error("expected a clang-named binary to be detected as clang")
+	}
+	if compilerIsClang(e, "/usr/bin/armv7a-cros-linux-gnueabihf-gcc") {
+		t.Error("expected a gcc-named binary not to be detected as clang")
+	}
+}
+
+func TestCompilerIsClangForceOverridesGccLookingName(t *testing.T) {
+	e := newFakeEnv(map[string]string{forceCompilerTypeEnvVar: "clang"})
+	if !compilerIsClang(e, "/usr/bin/cc") {
+		t.Error("expected the force override to treat a gcc-looking name as clang")
+	}
+}
+
+func TestCompilerIsClangForceOverridesClangLookingName(t *testing.T) {
+	e := newFakeEnv(map[string]string{forceCompilerTypeEnvVar: "gcc"})
+	if compilerIsClang(e, "/usr/bin/clang") {
+		t.Error("expected the force override to treat a clang-named binary as gcc")
+	}
+}
+
+func TestCompilerIsClangInvalidForceValueFallsBackToName(t *testing.T) {
+	e := newFakeEnv(map[string]string{forceCompilerTypeEnvVar: "bogus"})
+	if !compilerIsClang(e, "/usr/bin/clang") {
+		t.Error("expected an invalid override value to fall back to name-based detection")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+// config carries the compile-time choices baked into a given wrapper
+// instance (one per board/compiler combination). Fields are added here as
+// new per-config behaviors are introduced.
+type config struct {
+	// ConfigName identifies which wrapper configuration produced this
+	// binary, e.g. "amd64-generic" or "arm-chromeos".
+	ConfigName string
+
+	// ForceNoExceptions forces -fno-exceptions -fno-rtti onto every compile
+	// and strips any user-supplied -fexceptions/-frtti, for embedded configs
+	// that can't afford exception/RTTI support.
+	ForceNoExceptions bool
+
+	// Root is the build root that absolute paths are made relative to when
+	// RelativizePaths is enabled.
+	Root string
+
+	// RelativizePaths enables rewriting select absolute paths under Root to
+	// relative ones, so generated command lines don't leak the local build
+	// root into remote or reproducible builds.
+	RelativizePaths bool
+
+	// InjectBuildID adds -Wl,--build-id=sha1 to link commands, so every
+	// binary carries a build-id for crash symbolication.
+	InjectBuildID bool
+
+	// Reproducible enables steps that make compiler output independent of
+	// incidental build-environment state, such as deriving -frandom-seed
+	// from the output path instead of letting it default to something
+	// environment-dependent.
+	Reproducible bool
+
+	// ExtraIsystemDirs are system include directories injected into every
+	// compile for boards that need an extra global system include path.
+	// They're added as -isystem, which gcc and clang both search after -I,
+	// so a user's own -I still wins on a header name collision regardless
+	// of the order these are injected in.
+	ExtraIsystemDirs []string
+
+	// Target is the canonical target triple for this config, e.g.
+	// "armv7a-cros-linux-gnueabihf". It's the single source of truth for
+	// target derivation: other steps (clang -target injection, exporting it
+	// to the compiler's own child processes) read it rather than
+	// recomputing it.
+	Target string
+
+	// ArtifactsDir, when set, redirects per-compile side-output files (like
+	// -fsave-optimization-record's .opt.yaml) that would otherwise land in
+	// the cwd, so remote builds can collect them alongside the object file
+	// instead of losing them to a worker-local directory.
+	ArtifactsDir string
+
+	// CompileOnlyFlags are appended to every compile except configure-stage
+	// probes (see isInConfigureStage), for flags that would otherwise
+	// perturb autoconf-style feature detection.
+	CompileOnlyFlags []string
+
+	// DefaultOptLevel, when set, is injected as a -O flag for compiles that
+	// don't specify one of their own, so a config can pick a sane default
+	// optimization level without forcing it on callers who already chose
+	// one.
+	DefaultOptLevel string
+
+	// ForceNoCommon forces -fno-common onto every compile, unless the user
+	// passed -fcommon, for configs that want tentative-definition clashes
+	// to be multiple-definition link errors instead of silently merging.
+	ForceNoCommon bool
+
+	// LinkerPath, when set, is injected as -B<LinkerPath> for clang
+	// invocations, pointing clang at the board's chosen linker binary.
+	LinkerPath string
+
+	// DefaultMCPUByArch maps an architecture (the first "-"-separated
+	// component of a target triple, e.g. "armv7m") to the -mcpu value that
+	// should be injected for it when the user didn't specify their own
+	// -mcpu/-mtune.
+	DefaultMCPUByArch map[string]string
+
+	// InjectFullRelro adds -Wl,-z,now -Wl,-z,relro to link commands, for
+	// configs that want full RELRO hardening on every binary they produce.
+	InjectFullRelro bool
+
+	// DefaultIsysroot, when set, is injected as -isysroot <DefaultIsysroot>
+	// for compiles that don't specify their own, for macOS cross builds
+	// where clang needs an explicit SDK root rather than the host's.
+	DefaultIsysroot string
+
+	// OldWrapperPath, when set, points at a previous-generation wrapper
+	// binary invoked alongside this one for output comparison (see
+	// compareCommands). It should never stay set in production: doing so
+	// silently doubles the work of every compile.
+	OldWrapperPath string
+
+	// UseLlvmNext opts a config into llvm-next: an upcoming, not-yet-stable
+	// clang toolchain evaluated ahead of its promotion to the default.
+	UseLlvmNext bool
+
+	// LlvmNextFlags are appended to clang invocations when UseLlvmNext is
+	// set, e.g. extra warning suppressions needed until the new compiler's
+	// rough edges are sanded down.
+	LlvmNextFlags []string
+
+	// UseCCache is the board's configured default for whether ccache should
+	// wrap compiles (see ccacheDecision, which can still override it based
+	// on the command line or environment).
+	UseCCache bool
+
+	// ProbeResourceDir opts into running the compiler with
+	// -print-resource-dir (see probeResourceDir) and injecting the result
+	// back as an explicit -resource-dir flag, for clang toolchains where the
+	// resource dir clang resolves on its own doesn't match where this
+	// board's headers/builtins actually live.
+	ProbeResourceDir bool
+}
+
+// getConfig looks up a wrapper configuration by name.
+func getConfig(name string) (cfg *config, ok bool) {
+	cfg, ok = knownConfigs[name]
+	return cfg, ok
+}
+
+// knownConfigs enumerates the wrapper configurations built into this
+// binary. Each board/compiler pairing that needs the wrapper gets an entry
+// here; fields default to the permissive behavior unless a config opts in.
+var knownConfigs = map[string]*config{
+	"arm-embedded-hardened": {
+		ConfigName:        "arm-embedded-hardened",
+		ForceNoExceptions: true,
+	},
+	"android-arm64": {
+		ConfigName: "android-arm64",
+		Target:     "aarch64-linux-android",
+	},
+}
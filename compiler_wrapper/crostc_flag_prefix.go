@@ -0,0 +1,84 @@
+package main
+
+import "strings"
+
+// crostcFlagPrefix namespaces wrapper-consumed CLI options so a future
+// compiler release adding a same-named flag of its own can't collide with
+// one of ours.
+const crostcFlagPrefix = "--crostc-"
+
+// legacyWrapperFlagNames maps each wrapper-consumed option's legacy
+// (pre-prefix) spelling to its canonical --crostc- name, so existing
+// callers of the legacy form keep working.
+var legacyWrapperFlagNames = map[string]string{
+	"--gomacc-path": "--crostc-gomacc-path",
+}
+
+// canonicalWrapperFlagName splits arg into its flag name and "=value" (if
+// any), and maps the name to its canonical --crostc- form if it's a known
+// legacy spelling. Both "--crostc-gomacc-path=/path" and
+// "--gomacc-path=/path" resolve to the same (name, value) pair.
+func canonicalWrapperFlagName(arg string) (name, value string, hasValue bool) {
+	name = arg
+	if i := strings.IndexByte(arg, '='); i >= 0 {
+		name, value = arg[:i], arg[i+1:]
+		hasValue = true
+	}
+	if canonical, isLegacy := legacyWrapperFlagNames[name]; isLegacy {
+		name = canonical
+	}
+	return name, value, hasValue
+}
+
+// isWrapperConsumedFlag reports whether arg (in either its --crostc- or
+// legacy spelling) names a wrapper-consumed option that must never reach
+// the real compiler.
+func isWrapperConsumedFlag(arg string) bool {
+	name, _, _ := canonicalWrapperFlagName(arg)
+	return strings.HasPrefix(name, crostcFlagPrefix)
+}
+
+// stripWrapperConsumedFlags returns a copy of compilerCmd with every
+// wrapper-consumed option removed (in whichever spelling it was given),
+// plus a map from each found option's canonical name to its value, e.g.
+// {"--crostc-gomacc-path": "/path/to/gomacc"}.
+func stripWrapperConsumedFlags(compilerCmd *command) (*command, map[string]string) {
+	found := map[string]string{}
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if isWrapperConsumedFlag(arg) {
+			name, value, _ := canonicalWrapperFlagName(arg)
+			found[name] = value
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if len(found) == 0 {
+		return compilerCmd, found
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd, found
+}
+
+// applyWrapperConsumedFlags strips every wrapper-consumed option off
+// compilerCmd (see stripWrapperConsumedFlags) so none of them reach the
+// real compiler as an unrecognized flag, and forwards --crostc-gomacc-path
+// (or its legacy --gomacc-path spelling) on as a GOMACC_PATH override for
+// this invocation, matching how gomaPresent already reads GOMACC_PATH to
+// decide between goma and ccache.
+func applyWrapperConsumedFlags(compilerCmd *command) *command {
+	cmd, found := stripWrapperConsumedFlags(compilerCmd)
+	path, ok := found["--crostc-gomacc-path"]
+	if !ok || path == "" {
+		return cmd
+	}
+
+	newCmd := *cmd
+	newCmd.EnvUpdates = make(map[string]string, len(cmd.EnvUpdates)+1)
+	for k, v := range cmd.EnvUpdates {
+		newCmd.EnvUpdates[k] = v
+	}
+	newCmd.EnvUpdates["GOMACC_PATH"] = path
+	return &newCmd
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripWrapperConsumedFlagsNamespacedForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "--crostc-gomacc-path=/path/to/gomacc"}}
+
+	got, found := stripWrapperConsumedFlags(cmd)
+
+	want := []string{"-c", "foo.c"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+	if found["--crostc-gomacc-path"] != "/path/to/gomacc" {
+		t.Errorf("found = %v", found)
+	}
+}
+
+func TestStripWrapperConsumedFlagsLegacyForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "--gomacc-path=/path/to/gomacc"}}
+
+	got, found := stripWrapperConsumedFlags(cmd)
+
+	want := []string{"-c", "foo.c"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+	if found["--crostc-gomacc-path"] != "/path/to/gomacc" {
+		t.Errorf("expected the legacy form to resolve to the canonical name, found = %v", found)
+	}
+}
+
+func TestStripWrapperConsumedFlagsNoopWithoutWrapperFlags(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got, found := stripWrapperConsumedFlags(cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified")
+	}
+	if len(found) != 0 {
+		t.Errorf("expected no wrapper flags found, got %v", found)
+	}
+}
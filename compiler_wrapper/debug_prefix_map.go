@@ -0,0 +1,29 @@
+package main
+
+import "strings"
+
+// processDebugPrefixMap injects a prefix-map flag that rewrites cwd to "."
+// in debug info and other embedded paths, under the reproducibility gate,
+// so object files don't embed the local build root. Older gcc only
+// understands -fdebug-prefix-map; clang (and newer gcc) use
+// -ffile-prefix-map, which covers both debug info and __FILE__/#line
+// output. A user-supplied flag of either kind is left alone.
+func processDebugPrefixMap(cfg *config, compilerIsClang bool, cwd string, compilerCmd *command) *command {
+	if !cfg.Reproducible {
+		return compilerCmd
+	}
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-fdebug-prefix-map=") || strings.HasPrefix(arg, "-ffile-prefix-map=") {
+			return compilerCmd
+		}
+	}
+
+	flag := "-fdebug-prefix-map=" + cwd + "=."
+	if compilerIsClang {
+		flag = "-ffile-prefix-map=" + cwd + "=."
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), flag)
+	return &newCmd
+}
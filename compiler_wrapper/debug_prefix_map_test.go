@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestProcessDebugPrefixMapGcc(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDebugPrefixMap(cfg, false, "/build/work", cmd)
+	want := "-fdebug-prefix-map=/build/work=."
+	if last := got.Args[len(got.Args)-1]; last != want {
+		t.Errorf("got %q, want %q", last, want)
+	}
+}
+
+func TestProcessDebugPrefixMapClang(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDebugPrefixMap(cfg, true, "/build/work", cmd)
+	want := "-ffile-prefix-map=/build/work=."
+	if last := got.Args[len(got.Args)-1]; last != want {
+		t.Errorf("got %q, want %q", last, want)
+	}
+}
+
+func TestProcessDebugPrefixMapRespectsUserOverride(t *testing.T) {
+	cfg := &config{Reproducible: true}
+
+	cmdGcc := &command{Args: []string{"-c", "foo.c", "-fdebug-prefix-map=/a=/b"}}
+	if got := processDebugPrefixMap(cfg, false, "/build/work", cmdGcc); got != cmdGcc {
+		t.Errorf("expected gcc command to be returned unchanged, got %v", got.Args)
+	}
+
+	cmdClang := &command{Args: []string{"-c", "foo.c", "-ffile-prefix-map=/a=/b"}}
+	if got := processDebugPrefixMap(cfg, true, "/build/work", cmdClang); got != cmdClang {
+		t.Errorf("expected clang command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestProcessDebugPrefixMapDisabledByDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	if got := processDebugPrefixMap(cfg, true, "/build/work", cmd); got != cmd {
+		t.Errorf("expected no-op when Reproducible is false, got %v", got.Args)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import "regexp"
+
+// debugFlagPattern matches user debug-level flags (-g, -g0..-g3, -ggdb,
+// -ggdb0..-ggdb3) that processDebugStrip removes. It deliberately doesn't
+// match -gsplit-dwarf or other -g<word> variants, which are handled by a
+// separate debug-info step and shouldn't be touched here.
+var debugFlagPattern = regexp.MustCompile(`^-g(gdb)?[0-9]?$`)
+
+// processDebugStrip removes user-supplied debug-level flags and injects
+// -g0 when COMPILER_WRAPPER_STRIP_DEBUG is set, for size-optimized configs
+// that can't afford debug info. It runs after user args have otherwise been
+// processed.
+func processDebugStrip(e env, compilerCmd *command) *command {
+	if _, ok := e.getenv("COMPILER_WRAPPER_STRIP_DEBUG"); !ok {
+		return compilerCmd
+	}
+	newArgs := make([]string, 0, len(compilerCmd.Args)+1)
+	for _, arg := range compilerCmd.Args {
+		if debugFlagPattern.MatchString(arg) {
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	newArgs = append(newArgs, "-g0")
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
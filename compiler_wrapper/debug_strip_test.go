@@ -0,0 +1,50 @@
+package main
+
+import "testing"
+
+func TestProcessDebugStripRemovesVariantsAndInjectsG0(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_STRIP_DEBUG": "1"})
+	cases := [][]string{
+		{"-c", "foo.c", "-g"},
+		{"-c", "foo.c", "-g2"},
+		{"-c", "foo.c", "-ggdb"},
+		{"-c", "foo.c", "-ggdb3"},
+	}
+	for _, args := range cases {
+		got := processDebugStrip(e, &command{Args: args})
+		for _, a := range got.Args[:len(got.Args)-1] {
+			if debugFlagPattern.MatchString(a) {
+				t.Errorf("args %v: expected debug flag %q to be stripped", args, a)
+			}
+		}
+		if got.Args[len(got.Args)-1] != "-g0" {
+			t.Errorf("args %v: expected -g0 to be appended, got %v", args, got.Args)
+		}
+	}
+}
+
+func TestProcessDebugStripLeavesSplitDwarfAlone(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_STRIP_DEBUG": "1"})
+	got := processDebugStrip(e, &command{Args: []string{"-gsplit-dwarf", "-c", "foo.c"}})
+
+	found := false
+	for _, a := range got.Args {
+		if a == "-gsplit-dwarf" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected -gsplit-dwarf to survive, got %v", got.Args)
+	}
+}
+
+func TestProcessDebugStripNoopWithoutEnv(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-g", "-c", "foo.c"}}
+
+	got := processDebugStrip(e, cmd)
+
+	if got != cmd {
+		t.Error("expected command to be returned unmodified without the env var")
+	}
+}
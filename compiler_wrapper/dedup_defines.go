@@ -0,0 +1,49 @@
+package main
+
+import "strings"
+
+// dedupDefinesEnvVar opts into collapsing exact-duplicate -D definitions
+// (same name and value) down to their first occurrence. Off by default
+// since argument order can matter to some build systems; it's purely a
+// noise/cache-key-stability cleanup for builds that want it.
+const dedupDefinesEnvVar = "COMPILER_WRAPPER_DEDUP_DEFINES"
+
+// dedupDefinesEnabled reports whether dedupDefinesEnvVar is set to "1".
+func dedupDefinesEnabled(e env) bool {
+	v, ok := e.getenv(dedupDefinesEnvVar)
+	return ok && v == "1"
+}
+
+// processDedupDefines drops exact-duplicate "-D<name>=<value>" (or bare
+// "-Dname") arguments, keeping only the first occurrence of each. A later
+// -D for the same name with a *different* value is a real redefinition,
+// not noise, and is left alone rather than collapsed, since dropping it
+// would silently change which definition wins.
+func processDedupDefines(e env, compilerCmd *command) *command {
+	if !dedupDefinesEnabled(e) {
+		return compilerCmd
+	}
+
+	seen := map[string]bool{}
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if !strings.HasPrefix(arg, "-D") {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+		if seen[arg] {
+			changed = true
+			continue
+		}
+		seen[arg] = true
+		newArgs = append(newArgs, arg)
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
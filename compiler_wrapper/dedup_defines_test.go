@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestProcessDedupDefinesCollapsesExactDuplicates(t *testing.T) {
+	e := newFakeEnv(map[string]string{dedupDefinesEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "-DFOO=1", "-DFOO=1", "foo.c"}}
+
+	got := processDedupDefines(e, cmd)
+	want := []string{"-c", "-DFOO=1", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessDedupDefinesKeepsConflictingRedefinitions(t *testing.T) {
+	e := newFakeEnv(map[string]string{dedupDefinesEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "-DFOO=1", "-DFOO=2", "foo.c"}}
+
+	got := processDedupDefines(e, cmd)
+	if got != cmd {
+		t.Error("expected differing redefinitions to be preserved")
+	}
+}
+
+func TestProcessDedupDefinesNoopWhenDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-DFOO=1", "-DFOO=1", "foo.c"}}
+
+	got := processDedupDefines(e, cmd)
+	if got != cmd {
+		t.Error("expected no change when dedup is disabled")
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import "strings"
+
+// archFromTriple returns the first "-"-separated component of a target
+// triple, e.g. "armv7m" from "armv7m-cros-eabi".
+func archFromTriple(triple string) string {
+	if i := strings.IndexByte(triple, '-'); i >= 0 {
+		return triple[:i]
+	}
+	return triple
+}
+
+// hasUserMCPUFlag reports whether compilerCmd already carries a
+// user-specified -mcpu= or -mtune=, either of which means the wrapper's
+// own default shouldn't be injected on top.
+func hasUserMCPUFlag(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-mcpu=") || strings.HasPrefix(arg, "-mtune=") {
+			return true
+		}
+	}
+	return false
+}
+
+// processDefaultMCPU injects the -mcpu value cfg.DefaultMCPUByArch
+// declares for the config's target architecture, unless the user already
+// specified their own -mcpu/-mtune, or the config has no default for this
+// architecture.
+func processDefaultMCPU(cfg *config, compilerCmd *command) *command {
+	if hasUserMCPUFlag(compilerCmd) {
+		return compilerCmd
+	}
+	mcpu, ok := cfg.DefaultMCPUByArch[archFromTriple(cfg.Target)]
+	if !ok || mcpu == "" {
+		return compilerCmd
+	}
+	return addPreUserArgs(compilerCmd, []string{"-mcpu=" + mcpu})
+}
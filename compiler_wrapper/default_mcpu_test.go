@@ -0,0 +1,62 @@
+package main
+
+import "testing"
+
+func TestProcessDefaultMCPUInjectsWhenAbsent(t *testing.T) {
+	cfg := &config{
+		Target:            "armv7m-cros-eabi",
+		DefaultMCPUByArch: map[string]string{"armv7m": "cortex-m4"},
+	}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDefaultMCPU(cfg, cmd)
+	want := []string{"-mcpu=cortex-m4", "-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessDefaultMCPUSuppressedByUserFlag(t *testing.T) {
+	cfg := &config{
+		Target:            "armv7m-cros-eabi",
+		DefaultMCPUByArch: map[string]string{"armv7m": "cortex-m4"},
+	}
+	cmd := &command{Args: []string{"-c", "-mcpu=cortex-m0", "foo.c"}}
+
+	got := processDefaultMCPU(cfg, cmd)
+	if got != cmd {
+		t.Error("expected a user -mcpu to suppress the default")
+	}
+}
+
+func TestProcessDefaultMCPUArchSpecificSelection(t *testing.T) {
+	cfg := &config{
+		Target: "x86_64-cros-linux-gnu",
+		DefaultMCPUByArch: map[string]string{
+			"armv7m":  "cortex-m4",
+			"x86_64":  "skylake",
+			"aarch64": "cortex-a76",
+		},
+	}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDefaultMCPU(cfg, cmd)
+	if len(got.Args) == 0 || got.Args[0] != "-mcpu=skylake" {
+		t.Errorf("got %v, want -mcpu=skylake first", got.Args)
+	}
+}
+
+func TestProcessDefaultMCPUNoopWithoutConfigEntry(t *testing.T) {
+	cfg := &config{Target: "riscv64-cros-linux-gnu", DefaultMCPUByArch: map[string]string{"armv7m": "cortex-m4"}}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDefaultMCPU(cfg, cmd)
+	if got != cmd {
+		t.Error("expected no injection for an arch without a configured default")
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "strings"
+
+// hasUserOptLevelFlag reports whether compilerCmd already specifies an
+// optimization level, e.g. -O2 or -Os.
+func hasUserOptLevelFlag(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-O") {
+			return true
+		}
+	}
+	return false
+}
+
+// processDefaultOptLevel injects cfg.DefaultOptLevel ahead of the user's own
+// arguments when set and the user didn't already pass an optimization
+// level, so a config can pick a sane default without overriding a caller
+// who already chose one explicitly.
+func processDefaultOptLevel(cfg *config, compilerCmd *command) *command {
+	if cfg.DefaultOptLevel == "" || hasUserOptLevelFlag(compilerCmd) {
+		return compilerCmd
+	}
+	return addPreUserArgs(compilerCmd, []string{cfg.DefaultOptLevel})
+}
@@ -0,0 +1,36 @@
+package main
+
+import "testing"
+
+func TestProcessDefaultOptLevelInjectsWhenAbsent(t *testing.T) {
+	cfg := &config{DefaultOptLevel: "-O2"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDefaultOptLevel(cfg, cmd)
+
+	if got.Args[0] != "-O2" {
+		t.Errorf("Args[0] = %q, want -O2", got.Args[0])
+	}
+}
+
+func TestProcessDefaultOptLevelLeavesUserChoiceAlone(t *testing.T) {
+	cfg := &config{DefaultOptLevel: "-O2"}
+	cmd := &command{Args: []string{"-O0", "-c", "foo.c"}}
+
+	got := processDefaultOptLevel(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when the user already chose an opt level")
+	}
+}
+
+func TestProcessDefaultOptLevelNoopWhenUnset(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDefaultOptLevel(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when DefaultOptLevel is unset")
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+// determinismCheckEnabled reports whether
+// COMPILER_WRAPPER_DETERMINISM_CHECK is set, opting into compiling the
+// input twice and comparing the resulting object bytes. It's opt-in since
+// it doubles build time.
+func determinismCheckEnabled(e env) bool {
+	_, ok := e.getenv("COMPILER_WRAPPER_DETERMINISM_CHECK")
+	return ok
+}
+
+// runCompileFunc invokes compilerCmd so that its output lands at
+// outputPath. It's a function type so tests can substitute a mock compiler
+// for the real run path.
+type runCompileFunc func(compilerCmd *command, outputPath string) error
+
+// checkCompilerDeterminism compiles compilerCmd twice, into two temporary
+// output files via run, and compares the resulting bytes. It returns a
+// descriptive error if they differ, or if either compile fails.
+func checkCompilerDeterminism(run runCompileFunc, compilerCmd *command) error {
+	dir, err := os.MkdirTemp("", "crostc-determinism")
+	if err != nil {
+		return fmt.Errorf("determinism check: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	out1 := filepath.Join(dir, "out1")
+	out2 := filepath.Join(dir, "out2")
+	if err := run(compilerCmd, out1); err != nil {
+		return fmt.Errorf("determinism check: first compile: %w", err)
+	}
+	if err := run(compilerCmd, out2); err != nil {
+		return fmt.Errorf("determinism check: second compile: %w", err)
+	}
+
+	b1, err := os.ReadFile(out1)
+	if err != nil {
+		return fmt.Errorf("determinism check: %w", err)
+	}
+	b2, err := os.ReadFile(out2)
+	if err != nil {
+		return fmt.Errorf("determinism check: %w", err)
+	}
+	if !bytes.Equal(b1, b2) {
+		return fmt.Errorf("determinism check: compiler produced different output bytes across two runs of %q", compilerCmd.Path)
+	}
+	return nil
+}
+
+// runRealCompile returns a runCompileFunc that actually runs cmd, with its
+// output redirected to outputPath via a trailing -o (compilers use the
+// last -o they see). Its own stdout/stderr are discarded: the determinism
+// check only cares about the resulting object bytes, and a second full
+// build's worth of diagnostics would just be noise on top of the real
+// compile's own.
+func runRealCompile(e env) runCompileFunc {
+	return func(cmd *command, outputPath string) error {
+		args := append(append([]string{}, cmd.Args...), "-o", outputPath)
+		real := exec.Command(cmd.Path, args...)
+		real.Env = mergeEnvUpdates(e.environ(), cmd.EnvUpdates)
+		return real.Run()
+	}
+}
+
+// maybeCheckCompilerDeterminism runs checkCompilerDeterminism against
+// compilerCmd when COMPILER_WRAPPER_DETERMINISM_CHECK is set, reporting any
+// mismatch to stderr. It never blocks the real compile that follows it: the
+// check is purely diagnostic, so a bug in it must not be able to fail an
+// otherwise-good build.
+func maybeCheckCompilerDeterminism(e env, compilerCmd *command) {
+	if !determinismCheckEnabled(e) {
+		return
+	}
+	if err := checkCompilerDeterminism(runRealCompile(e), compilerCmd); err != nil {
+		fmt.Fprintln(e.stderr(), err)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDeterminismCheckEnabled(t *testing.T) {
+	if determinismCheckEnabled(newFakeEnv(map[string]string{})) {
+		t.Error("expected the check to be off by default")
+	}
+	if !determinismCheckEnabled(newFakeEnv(map[string]string{"COMPILER_WRAPPER_DETERMINISM_CHECK": "1"})) {
+		t.Error("expected the check to be on when the env var is set")
+	}
+}
+
+func TestCheckCompilerDeterminismIdenticalOutputs(t *testing.T) {
+	mock := func(compilerCmd *command, outputPath string) error {
+		return os.WriteFile(outputPath, []byte("same bytes"), 0o644)
+	}
+	if err := checkCompilerDeterminism(mock, &command{Path: "cc"}); err != nil {
+		t.Errorf("expected no error for identical outputs, got: %v", err)
+	}
+}
+
+func TestCheckCompilerDeterminismDifferingOutputs(t *testing.T) {
+	calls := 0
+	mock := func(compilerCmd *command, outputPath string) error {
+		calls++
+		content := "run1"
+		if calls == 2 {
+			content = "run2"
+		}
+		return os.WriteFile(outputPath, []byte(content), 0o644)
+	}
+	if err := checkCompilerDeterminism(mock, &command{Path: "cc"}); err == nil {
+		t.Error("expected an error for differing outputs")
+	}
+}
+
+func TestCheckCompilerDeterminismCompileFailure(t *testing.T) {
+	mock := func(compilerCmd *command, outputPath string) error {
+		return os.ErrPermission
+	}
+	if err := checkCompilerDeterminism(mock, &command{Path: "cc"}); err == nil {
+		t.Error("expected an error when the underlying compile fails")
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+// diagnosticsAbsolutePathsFlag makes clang print absolute paths in its
+// diagnostics, which differ across machines and break golden diffing of
+// captured stderr for reproducible/remote builds.
+const diagnosticsAbsolutePathsFlag = "-fdiagnostics-absolute-paths"
+
+// processDiagnosticsAbsolutePaths strips -fdiagnostics-absolute-paths when
+// cfg.Reproducible is set, whether it came from the user or an earlier
+// config-injection step, so diagnostic output stays relative and
+// comparable across machines. It's a no-op when the gate is off.
+func processDiagnosticsAbsolutePaths(cfg *config, compilerCmd *command) *command {
+	if !cfg.Reproducible {
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg == diagnosticsAbsolutePathsFlag {
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
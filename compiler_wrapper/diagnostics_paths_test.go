@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestProcessDiagnosticsAbsolutePathsStripsUnderGate(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "-fdiagnostics-absolute-paths", "foo.c"}}
+
+	got := processDiagnosticsAbsolutePaths(cfg, cmd)
+
+	want := []string{"-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessDiagnosticsAbsolutePathsKeptWithoutGate(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "-fdiagnostics-absolute-paths", "foo.c"}}
+
+	got := processDiagnosticsAbsolutePaths(cfg, cmd)
+	if got != cmd {
+		t.Error("expected the flag to survive when Reproducible is off")
+	}
+}
+
+func TestProcessDiagnosticsAbsolutePathsNoopWithoutFlag(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processDiagnosticsAbsolutePaths(cfg, cmd)
+	if got != cmd {
+		t.Error("expected no change when the flag isn't present")
+	}
+}
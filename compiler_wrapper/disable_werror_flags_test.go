@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestDisableWerrorFlagsDedupesAlreadyPresentFlag(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-Wno-error=foo"}}
+	stderr := "foo.c:1:1: error: x [-Werror,-Wfoo]\n"
+
+	got := disableWerrorFlags(cmd, stderr)
+
+	count := 0
+	for _, a := range got.Args {
+		if a == "-Wno-error=foo" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one -Wno-error=foo, got %d in %v", count, got.Args)
+	}
+}
+
+func TestDisableWerrorFlagsAppendsBroadWnoError(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	got := disableWerrorFlags(cmd, "")
+
+	found := false
+	for _, a := range got.Args {
+		if a == "-Wno-error" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a broad -Wno-error to be appended, got %v", got.Args)
+	}
+}
+
+func TestDedupeFlagsPreservesOrder(t *testing.T) {
+	got := dedupeFlags([]string{"-a", "-b", "-a", "-c", "-b"})
+	want := []string{"-a", "-b", "-c"}
+	if len(got) != len(want) {
+		t.Fatalf("dedupeFlags(...) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("dedupeFlags(...) = %v, want %v", got, want)
+		}
+	}
+}
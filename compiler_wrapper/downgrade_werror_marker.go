@@ -0,0 +1,56 @@
+package main
+
+import "strings"
+
+// downgradeWerrorMarker, when present on a command, asks for -Werror flags
+// to be downgraded to -Wno-error immediately, in the single build, rather
+// than via the usual double-build retry (see werror.go/wnoerror.go). It
+// exists for ebuilds that set a project-wide -Werror but want it relaxed
+// for one package without paying for a second compile.
+const downgradeWerrorMarker = "-D_CROSTC_DOWNGRADE_WERROR"
+
+// hasDowngradeWerrorMarker reports whether compilerCmd carries
+// downgradeWerrorMarker.
+func hasDowngradeWerrorMarker(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == downgradeWerrorMarker {
+			return true
+		}
+	}
+	return false
+}
+
+// downgradeWerrorFlag rewrites a single -Werror/-Werror=<name> argument to
+// its -Wno-error equivalent, leaving every other argument (including
+// unrelated -W flags) unchanged.
+func downgradeWerrorFlag(arg string) string {
+	if arg == "-Werror" {
+		return "-Wno-error"
+	}
+	if name, ok := strings.CutPrefix(arg, "-Werror="); ok {
+		return "-Wno-error=" + name
+	}
+	return arg
+}
+
+// processDowngradeWerrorMarker strips downgradeWerrorMarker and, when
+// present, downgrades every -Werror/-Werror=<name> argument in the same
+// pass, so the single build behaves as if -Werror had never been passed at
+// all. It's a no-op when the marker isn't present.
+func processDowngradeWerrorMarker(compilerCmd *command) *command {
+	if !hasDowngradeWerrorMarker(compilerCmd) {
+		return compilerCmd
+	}
+
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg == downgradeWerrorMarker {
+			continue
+		}
+		newArgs = append(newArgs, downgradeWerrorFlag(arg))
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
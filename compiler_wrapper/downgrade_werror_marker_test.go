@@ -0,0 +1,38 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessDowngradeWerrorMarkerDowngradesInOnePass(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", downgradeWerrorMarker, "-Werror=foo", "-Werror"}}
+
+	got := processDowngradeWerrorMarker(cmd)
+
+	want := []string{"-c", "foo.c", "-Wno-error=foo", "-Wno-error"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessDowngradeWerrorMarkerNoopWithoutMarker(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-Werror=foo"}}
+
+	got := processDowngradeWerrorMarker(cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified without the marker")
+	}
+}
+
+func TestProcessDowngradeWerrorMarkerLeavesUnrelatedWFlagsAlone(t *testing.T) {
+	cmd := &command{Args: []string{downgradeWerrorMarker, "-Wall", "-Wextra"}}
+
+	got := processDowngradeWerrorMarker(cmd)
+
+	want := []string{"-Wall", "-Wextra"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os/exec"
+	"strings"
+)
+
+// dumpIncludePathsFlag is a synthetic driver flag, intercepted before the
+// real compiler invocation is built, that dumps the effective include
+// search path as a JSON array for IDE integration instead of compiling.
+const dumpIncludePathsFlag = "--wrapper-dump-include-paths"
+
+// isDumpIncludePathsCommand reports whether compilerCmd asked to dump
+// include search paths rather than compile.
+func isDumpIncludePathsCommand(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == dumpIncludePathsFlag {
+			return true
+		}
+	}
+	return false
+}
+
+// dumpIncludePaths runs the real compiler with -E -v on an empty input to
+// discover its effective include search path, and returns it JSON-encoded
+// as a simple array of strings.
+func dumpIncludePaths(compilerCmd *command) ([]byte, error) {
+	args := make([]string, 0, len(compilerCmd.Args)+3)
+	for _, arg := range compilerCmd.Args {
+		if arg == dumpIncludePathsFlag {
+			continue
+		}
+		args = append(args, arg)
+	}
+	args = append(args, "-E", "-v", "-x", "c", "-")
+
+	cmd := exec.Command(compilerCmd.Path, args...)
+	cmd.Stdin = strings.NewReader("")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	// The search-path banner is written to stderr regardless of whether the
+	// (trivial, empty) compile itself succeeds, so errors here are ignored.
+	cmd.Run()
+
+	paths := parseIncludeSearchPaths(stderr.String())
+	return json.Marshal(paths)
+}
+
+// parseIncludeSearchPaths extracts the directory list between clang/gcc's
+// "#include <...> search starts here:" banner and the following "End of
+// search list." line, as emitted by `-E -v`.
+func parseIncludeSearchPaths(verboseOutput string) []string {
+	const start = "#include <...> search starts here:"
+	const end = "End of search list."
+
+	lines := strings.Split(verboseOutput, "\n")
+	var paths []string
+	inList := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.Contains(trimmed, start):
+			inList = true
+			continue
+		case strings.Contains(trimmed, end):
+			inList = false
+			continue
+		case inList:
+			// Some compilers annotate entries, e.g. "/usr/include (framework
+			// directory)"; keep only the path itself.
+			if i := strings.Index(trimmed, " ("); i >= 0 {
+				trimmed = trimmed[:i]
+			}
+			if trimmed != "" {
+				paths = append(paths, trimmed)
+			}
+		}
+	}
+	return paths
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestIsDumpIncludePathsCommand(t *testing.T) {
+	if !isDumpIncludePathsCommand(&command{Args: []string{"--wrapper-dump-include-paths"}}) {
+		t.Error("expected the flag to be detected")
+	}
+	if isDumpIncludePathsCommand(&command{Args: []string{"-c", "foo.c"}}) {
+		t.Error("expected an ordinary compile not to be detected")
+	}
+}
+
+const sampleVerboseBanner = `ignoring nonexistent directory "/usr/local/include"
+#include "..." search starts here:
+#include <...> search starts here:
+ /usr/lib/gcc/x86_64-linux-gnu/12/include
+ /usr/local/include
+ /usr/include/x86_64-linux-gnu
+ /usr/include
+End of search list.
+`
+
+func TestParseIncludeSearchPaths(t *testing.T) {
+	got := parseIncludeSearchPaths(sampleVerboseBanner)
+	want := []string{
+		"/usr/lib/gcc/x86_64-linux-gnu/12/include",
+		"/usr/local/include",
+		"/usr/include/x86_64-linux-gnu",
+		"/usr/include",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("path %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseIncludeSearchPathsStripsAnnotations(t *testing.T) {
+	banner := "#include <...> search starts here:\n" +
+		" /usr/include (framework directory)\n" +
+		"End of search list.\n"
+	got := parseIncludeSearchPaths(banner)
+	want := []string{"/usr/include"}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDumpIncludePathsEmitsValidJSON(t *testing.T) {
+	cmd := &command{
+		Path: "/bin/sh",
+		Args: []string{"-c", "cat >/dev/null; cat >&2 <<'EOF'\n#include <...> search starts here:\n /fake/include\nEnd of search list.\nEOF", "--wrapper-dump-include-paths"},
+	}
+
+	out, err := dumpIncludePaths(cmd)
+	if err != nil {
+		t.Fatalf("dumpIncludePaths: %v", err)
+	}
+
+	var paths []string
+	if err := json.Unmarshal(out, &paths); err != nil {
+		t.Fatalf("json.Unmarshal(%s): %v", out, err)
+	}
+	if len(paths) != 1 || paths[0] != "/fake/include" {
+		t.Errorf("got %v, want [/fake/include]", paths)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// dumpParentsEnabled reports whether COMPILER_WRAPPER_DUMP_PARENTS=1 is
+// set, opting into writing the parent-process chain to a file on every
+// invocation, not just the werror-report path.
+func dumpParentsEnabled(e env) bool {
+	v, ok := e.getenv("COMPILER_WRAPPER_DUMP_PARENTS")
+	return ok && v == "1"
+}
+
+// dumpParentsPath returns the file COMPILER_WRAPPER_DUMP_PARENTS_PATH asks
+// the chain to be written to, falling back to a fixed default so the
+// feature has somewhere to write even if only the enabling var is set.
+func dumpParentsPath(e env) string {
+	if v, ok := e.getenv("COMPILER_WRAPPER_DUMP_PARENTS_PATH"); ok && v != "" {
+		return v
+	}
+	return "/tmp/compiler_wrapper_parents.json"
+}
+
+// maybeDumpParents writes pid's parent-process chain to
+// dumpParentsPath(e), for build-graph debugging, regardless of whether the
+// compile itself later succeeds or fails. It's a no-op unless
+// dumpParentsEnabled(e); collection and write errors are swallowed, since
+// this is a best-effort diagnostic, not something that should fail a build.
+func maybeDumpParents(e env, pid int) {
+	if !dumpParentsEnabled(e) {
+		return
+	}
+	chain, _, err := collectAllParentProcesses(e, pid)
+	if err != nil && chain == nil {
+		return
+	}
+	data, err := json.Marshal(chain)
+	if err != nil {
+		return
+	}
+	os.WriteFile(dumpParentsPath(e), data, 0o644)
+}
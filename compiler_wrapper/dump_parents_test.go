@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDumpParentsEnabled(t *testing.T) {
+	if dumpParentsEnabled(newFakeEnv(map[string]string{})) {
+		t.Error("expected dump-parents to be off by default")
+	}
+	if !dumpParentsEnabled(newFakeEnv(map[string]string{"COMPILER_WRAPPER_DUMP_PARENTS": "1"})) {
+		t.Error("expected dump-parents to be on when the var is \"1\"")
+	}
+}
+
+func TestMaybeDumpParentsWritesFileOnSuccessfulCompile(t *testing.T) {
+	procDir := t.TempDir()
+	writeFakeProc(t, procDir, 3, 2, "leaf", "leaf\x00")
+	writeFakeProc(t, procDir, 2, 1, "mid", "mid\x00")
+	withProcRoot(t, procDir)
+
+	outPath := filepath.Join(t.TempDir(), "parents.json")
+	e := newFakeEnv(map[string]string{
+		"COMPILER_WRAPPER_DUMP_PARENTS":      "1",
+		"COMPILER_WRAPPER_DUMP_PARENTS_PATH": outPath,
+	})
+
+	maybeDumpParents(e, 3)
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("expected the parents file to be written, got: %v", err)
+	}
+	var chain []parentProcessInfo
+	if err := json.Unmarshal(data, &chain); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if len(chain) != 2 || chain[0].PID != 3 || chain[1].PID != 2 {
+		t.Errorf("got %+v", chain)
+	}
+}
+
+func TestMaybeDumpParentsNoopWhenDisabled(t *testing.T) {
+	outPath := filepath.Join(t.TempDir(), "parents.json")
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_DUMP_PARENTS_PATH": outPath})
+
+	maybeDumpParents(e, 3)
+
+	if _, err := os.Stat(outPath); !os.IsNotExist(err) {
+		t.Errorf("expected no file to be written when disabled")
+	}
+}
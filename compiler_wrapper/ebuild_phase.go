@@ -0,0 +1,37 @@
+package main
+
+// isInConfigureStage reports whether Portage's EBUILD_PHASE says this
+// invocation is part of a package's configure step (autoconf-style feature
+// probing), as opposed to its actual build.
+func isInConfigureStage(e env) bool {
+	v, ok := e.getenv("EBUILD_PHASE")
+	return ok && v == "configure"
+}
+
+// isInCompilePhase is the inverse of isInConfigureStage: it's true for
+// every phase except configure, including when EBUILD_PHASE is unset
+// entirely (e.g. invocations outside Portage), since those should behave
+// like a normal build rather than a feature probe.
+func isInCompilePhase(e env) bool {
+	return !isInConfigureStage(e)
+}
+
+// isInTestStage reports whether Portage's EBUILD_PHASE says this invocation
+// is part of a package's own src_test step, as opposed to its real build.
+func isInTestStage(e env) bool {
+	v, ok := e.getenv("EBUILD_PHASE")
+	return ok && v == "test"
+}
+
+// processCompileOnlyFlags appends cfg.CompileOnlyFlags, flags that should
+// only apply to real builds, unless this invocation is a configure-stage
+// probe -- injecting them there risks perturbing autoconf's feature
+// detection.
+func processCompileOnlyFlags(e env, cfg *config, compilerCmd *command) *command {
+	if len(cfg.CompileOnlyFlags) == 0 || !isInCompilePhase(e) {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), cfg.CompileOnlyFlags...)
+	return &newCmd
+}
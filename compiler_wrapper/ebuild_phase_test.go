@@ -0,0 +1,76 @@
+package main
+
+import "testing"
+
+func TestIsInConfigureStage(t *testing.T) {
+	if !isInConfigureStage(newFakeEnv(map[string]string{"EBUILD_PHASE": "configure"})) {
+		t.Error("expected EBUILD_PHASE=configure to be detected")
+	}
+	if isInConfigureStage(newFakeEnv(map[string]string{"EBUILD_PHASE": "compile"})) {
+		t.Error("expected EBUILD_PHASE=compile not to be a configure stage")
+	}
+	if isInConfigureStage(newFakeEnv(map[string]string{})) {
+		t.Error("expected no EBUILD_PHASE not to be a configure stage")
+	}
+}
+
+func TestIsInCompilePhase(t *testing.T) {
+	if isInCompilePhase(newFakeEnv(map[string]string{"EBUILD_PHASE": "configure"})) {
+		t.Error("expected configure not to count as the compile phase")
+	}
+	if !isInCompilePhase(newFakeEnv(map[string]string{"EBUILD_PHASE": "compile"})) {
+		t.Error("expected EBUILD_PHASE=compile to count as the compile phase")
+	}
+	if !isInCompilePhase(newFakeEnv(map[string]string{})) {
+		t.Error("expected no EBUILD_PHASE to count as the compile phase")
+	}
+}
+
+func TestIsInTestStage(t *testing.T) {
+	if !isInTestStage(newFakeEnv(map[string]string{"EBUILD_PHASE": "test"})) {
+		t.Error("expected EBUILD_PHASE=test to be detected")
+	}
+	if isInTestStage(newFakeEnv(map[string]string{"EBUILD_PHASE": "compile"})) {
+		t.Error("expected EBUILD_PHASE=compile not to be the test stage")
+	}
+	if isInTestStage(newFakeEnv(map[string]string{})) {
+		t.Error("expected no EBUILD_PHASE not to be the test stage")
+	}
+}
+
+func TestProcessCompileOnlyFlagsAbsentDuringConfigure(t *testing.T) {
+	cfg := &config{CompileOnlyFlags: []string{"-DCOMPILE_ONLY"}}
+	e := newFakeEnv(map[string]string{"EBUILD_PHASE": "configure"})
+	cmd := &command{Args: []string{"-c", "conftest.c"}}
+
+	got := processCompileOnlyFlags(e, cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected no injection during configure, got %v", got.Args)
+	}
+}
+
+func TestProcessCompileOnlyFlagsPresentDuringCompile(t *testing.T) {
+	cfg := &config{CompileOnlyFlags: []string{"-DCOMPILE_ONLY"}}
+	e := newFakeEnv(map[string]string{"EBUILD_PHASE": "compile"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processCompileOnlyFlags(e, cfg, cmd)
+	want := []string{"-c", "foo.c", "-DCOMPILE_ONLY"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessCompileOnlyFlagsNoopWhenUnconfigured(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	got := processCompileOnlyFlags(newFakeEnv(map[string]string{}), cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected no-op without CompileOnlyFlags, got %v", got.Args)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+)
+
+// env abstracts process environment and standard streams so tests can
+// substitute a fake implementation instead of touching real process state.
+type env interface {
+	getenv(key string) (string, bool)
+	environ() []string
+	stderr() io.Writer
+}
+
+// processEnv is the env implementation used by the real wrapper binary.
+type processEnv struct{}
+
+func newProcessEnv() env {
+	return processEnv{}
+}
+
+func (processEnv) getenv(key string) (string, bool) {
+	return os.LookupEnv(key)
+}
+
+func (processEnv) environ() []string {
+	return os.Environ()
+}
+
+func (processEnv) stderr() io.Writer {
+	return os.Stderr
+}
+
+// fakeEnv is an in-memory env used by tests. Callers can inspect stderrBuf
+// after the test to assert on anything the wrapper logged.
+type fakeEnv struct {
+	vars      map[string]string
+	stderrBuf bytes.Buffer
+}
+
+func newFakeEnv(vars map[string]string) *fakeEnv {
+	return &fakeEnv{vars: vars}
+}
+
+func (e *fakeEnv) getenv(key string) (string, bool) {
+	v, ok := e.vars[key]
+	return v, ok
+}
+
+func (e *fakeEnv) environ() []string {
+	out := make([]string, 0, len(e.vars))
+	for k, v := range e.vars {
+		out = append(out, k+"="+v)
+	}
+	return out
+}
+
+func (e *fakeEnv) stderr() io.Writer {
+	return &e.stderrBuf
+}
@@ -0,0 +1,53 @@
+package main
+
+import "strings"
+
+// envAllowlist parses COMPILER_WRAPPER_ENV_ALLOWLIST, a comma-separated
+// list of env var names, into a lookup set. ok is false when the var is
+// unset, meaning no filtering should happen at all.
+func envAllowlist(e env) (allowlist map[string]bool, ok bool) {
+	v, set := e.getenv("COMPILER_WRAPPER_ENV_ALLOWLIST")
+	if !set {
+		return nil, false
+	}
+	allowlist = map[string]bool{}
+	for _, name := range strings.Split(v, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			allowlist[name] = true
+		}
+	}
+	return allowlist, true
+}
+
+// filterEnviron drops entries from environ whose key isn't PATH and isn't
+// in allowlist. Entries without a "=" are kept as-is, since they aren't
+// ordinary KEY=VALUE vars.
+func filterEnviron(environ []string, allowlist map[string]bool) []string {
+	filtered := make([]string, 0, len(environ))
+	for _, kv := range environ {
+		i := strings.IndexByte(kv, '=')
+		if i < 0 {
+			filtered = append(filtered, kv)
+			continue
+		}
+		key := kv[:i]
+		if key == "PATH" || allowlist[key] {
+			filtered = append(filtered, kv)
+		}
+	}
+	return filtered
+}
+
+// buildExecEnviron computes the environment a child compiler process
+// should see: e's environ, filtered to COMPILER_WRAPPER_ENV_ALLOWLIST (plus
+// PATH) when that's set, with compilerCmd.EnvUpdates always applied on top
+// regardless of the allowlist, since those are the wrapper's own explicit
+// overrides rather than ambient environment.
+func buildExecEnviron(e env, compilerCmd *command) []string {
+	environ := e.environ()
+	if allowlist, ok := envAllowlist(e); ok {
+		environ = filterEnviron(environ, allowlist)
+	}
+	return mergeEnvUpdates(environ, compilerCmd.EnvUpdates)
+}
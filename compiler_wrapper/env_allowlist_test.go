@@ -0,0 +1,68 @@
+package main
+
+import "testing"
+
+func contains(environ []string, kv string) bool {
+	for _, x := range environ {
+		if x == kv {
+			return true
+		}
+	}
+	return false
+}
+
+func TestEnvAllowlistUnset(t *testing.T) {
+	if _, ok := envAllowlist(newFakeEnv(map[string]string{})); ok {
+		t.Error("expected no allowlist when the var is unset")
+	}
+}
+
+func TestEnvAllowlistParsesCommaSeparatedNames(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_ENV_ALLOWLIST": "HOME, TMPDIR ,LANG"})
+	allowlist, ok := envAllowlist(e)
+	if !ok {
+		t.Fatal("expected the allowlist to be present")
+	}
+	for _, name := range []string{"HOME", "TMPDIR", "LANG"} {
+		if !allowlist[name] {
+			t.Errorf("expected %s to be in the allowlist", name)
+		}
+	}
+}
+
+func TestBuildExecEnvironFiltersNonAllowlisted(t *testing.T) {
+	e := newFakeEnv(map[string]string{
+		"COMPILER_WRAPPER_ENV_ALLOWLIST": "LANG",
+		"PATH":                           "/usr/bin",
+		"LANG":                           "en_US.UTF-8",
+		"SECRET_TOKEN":                   "shh",
+	})
+
+	got := buildExecEnviron(e, &command{})
+	if !contains(got, "PATH=/usr/bin") {
+		t.Errorf("expected PATH to always survive filtering, got %v", got)
+	}
+	if !contains(got, "LANG=en_US.UTF-8") {
+		t.Errorf("expected allowlisted LANG to survive filtering, got %v", got)
+	}
+	if contains(got, "SECRET_TOKEN=shh") {
+		t.Errorf("expected non-allowlisted SECRET_TOKEN to be dropped, got %v", got)
+	}
+}
+
+func TestBuildExecEnvironEnvUpdatesAlwaysWin(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_ENV_ALLOWLIST": "LANG"})
+
+	got := buildExecEnviron(e, &command{EnvUpdates: map[string]string{"NOT_ALLOWLISTED": "still-set"}})
+	if !contains(got, "NOT_ALLOWLISTED=still-set") {
+		t.Errorf("expected EnvUpdates to be applied regardless of the allowlist, got %v", got)
+	}
+}
+
+func TestBuildExecEnvironNoFilteringWithoutAllowlist(t *testing.T) {
+	e := newFakeEnv(map[string]string{"SECRET_TOKEN": "shh"})
+	got := buildExecEnviron(e, &command{})
+	if !contains(got, "SECRET_TOKEN=shh") {
+		t.Errorf("expected no filtering without COMPILER_WRAPPER_ENV_ALLOWLIST, got %v", got)
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+// processForceNoExceptions enforces -fno-exceptions -fno-rtti for configs
+// that opt in via config.ForceNoExceptions. Any user-supplied positive forms
+// (-fexceptions, -frtti) are stripped first so they can't silently win by
+// argument ordering.
+func processForceNoExceptions(cfg *config, compilerCmd *command) *command {
+	if !cfg.ForceNoExceptions {
+		return compilerCmd
+	}
+	newArgs := make([]string, 0, len(compilerCmd.Args)+2)
+	for _, arg := range compilerCmd.Args {
+		if arg == "-fexceptions" || arg == "-frtti" {
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	newArgs = append(newArgs, "-fno-exceptions", "-fno-rtti")
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
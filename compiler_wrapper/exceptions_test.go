@@ -0,0 +1,51 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessForceNoExceptionsInjectsFlags(t *testing.T) {
+	cfg := &config{ForceNoExceptions: true}
+	cmd := &command{Args: []string{"-c", "foo.cc", "-o", "foo.o"}}
+
+	got := processForceNoExceptions(cfg, cmd)
+
+	want := []string{"-c", "foo.cc", "-o", "foo.o", "-fno-exceptions", "-fno-rtti"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessForceNoExceptionsRemovesConflictingFlags(t *testing.T) {
+	cfg := &config{ForceNoExceptions: true}
+	cmd := &command{Args: []string{"-fexceptions", "-c", "foo.cc", "-frtti"}}
+
+	got := processForceNoExceptions(cfg, cmd)
+
+	want := []string{"-c", "foo.cc", "-fno-exceptions", "-fno-rtti"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessForceNoExceptionsLeavesOtherConfigsUnaffected(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-fexceptions", "-c", "foo.cc"}}
+
+	got := processForceNoExceptions(cfg, cmd)
+
+	if got != cmd {
+		t.Errorf("expected cmd to be returned unmodified, got %v", got)
+	}
+}
+
+func TestKnownConfigOptsInToForceNoExceptions(t *testing.T) {
+	cfg, ok := knownConfigs["arm-embedded-hardened"]
+	if !ok {
+		t.Fatal("expected arm-embedded-hardened config to exist")
+	}
+	if !cfg.ForceNoExceptions {
+		t.Error("expected arm-embedded-hardened to opt into ForceNoExceptions")
+	}
+}
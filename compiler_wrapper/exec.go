@@ -0,0 +1,107 @@
+package main
+
+import "strings"
+
+// execStrategy selects which underlying mechanism processEnv.exec uses to
+// replace the wrapper process with the real compiler.
+type execStrategy string
+
+const (
+	// execStrategyExecv calls libc's execv(3) via cgo, after applying
+	// EnvUpdates into the process environment with libc setenv. This is the
+	// default: the sandbox's LD_PRELOAD-based syscall interception only
+	// observes libc's own calls, so it works where a raw Go syscall.Exec
+	// would not.
+	execStrategyExecv execStrategy = "execv"
+	// execStrategyExecve calls libc's execve(3) via cgo with an explicit
+	// envp built from EnvUpdates, instead of mutating the process
+	// environment first.
+	execStrategyExecve execStrategy = "execve"
+	// execStrategyGo applies EnvUpdates with os.Setenv/os.Unsetenv and execs
+	// via the Go runtime's syscall.Exec, with no cgo dependency.
+	execStrategyGo execStrategy = "go"
+)
+
+// selectExecStrategy reads COMPILER_WRAPPER_EXEC_STRATEGY from e's
+// environment, defaulting to execv. An unrecognized value also falls back
+// to execv rather than failing the build.
+func selectExecStrategy(e env) execStrategy {
+	v, ok := e.getenv("COMPILER_WRAPPER_EXEC_STRATEGY")
+	if !ok {
+		return execStrategyExecv
+	}
+	switch execStrategy(v) {
+	case execStrategyExecv, execStrategyExecve, execStrategyGo:
+		return execStrategy(v)
+	default:
+		return execStrategyExecv
+	}
+}
+
+// noLibcExecEnabled reports whether COMPILER_WRAPPER_NO_LIBC_EXEC=1 is set,
+// forcing the cgo-free Go exec path regardless of the selected strategy.
+// It exists for operators debugging sandbox issues in a cgo build, where
+// bypassing libc's execv/execve entirely can rule out the libc call itself
+// as the culprit.
+func noLibcExecEnabled(e env) bool {
+	v, ok := e.getenv("COMPILER_WRAPPER_NO_LIBC_EXEC")
+	return ok && v == "1"
+}
+
+// effectiveExecStrategy is selectExecStrategy with noLibcExecEnabled
+// applied on top: when set, it overrides whatever COMPILER_WRAPPER_EXEC_STRATEGY
+// requested.
+func effectiveExecStrategy(e env) execStrategy {
+	if noLibcExecEnabled(e) {
+		return execStrategyGo
+	}
+	return selectExecStrategy(e)
+}
+
+// exec replaces the current process with compilerCmd, using the strategy
+// selected from e's environment. If compilerCmd.Path doesn't exist, it
+// retries once against COMPILER_WRAPPER_FALLBACK_COMPILER (see
+// execWithFallback), if one is configured. If it still doesn't exist after
+// that, the error is wrapped as a compilerNotFoundError so callers can
+// report exitCodeForExecError's 127 instead of a generic internal error.
+func (processEnv) exec(e env, compilerCmd *command) error {
+	doExec := func(cmd *command) error {
+		switch effectiveExecStrategy(e) {
+		case execStrategyExecve:
+			return libcExecve(e, cmd)
+		case execStrategyGo:
+			return goExec(e, cmd)
+		default:
+			return libcExecv(cmd)
+		}
+	}
+	err := execWithFallback(e, compilerCmd, doExec)
+	return wrapExecNotFoundError(compilerCmd.Path, err)
+}
+
+// mergeEnvUpdates overlays updates onto a base "KEY=VALUE" environment
+// slice, replacing existing entries for keys present in updates and
+// appending any new ones. The base slice is not mutated.
+func mergeEnvUpdates(base []string, updates map[string]string) []string {
+	remaining := make(map[string]string, len(updates))
+	for k, v := range updates {
+		remaining[k] = v
+	}
+	merged := make([]string, 0, len(base)+len(updates))
+	for _, kv := range base {
+		key := kv
+		if i := strings.IndexByte(kv, '='); i >= 0 {
+			key = kv[:i]
+		}
+		if v, ok := remaining[key]; ok {
+			merged = append(merged, key+"="+v)
+			delete(remaining, key)
+			continue
+		}
+		merged = append(merged, kv)
+	}
+	for k, v := range remaining {
+		merged = append(merged, k+"="+v)
+	}
+	return merged
+}
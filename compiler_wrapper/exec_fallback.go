@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+	"os"
+)
+
+// fallbackCompilerEnvVar names a secondary compiler binary to retry with if
+// the primary one can't be exec'd at all (missing or corrupt), for recovery
+// workflows that want a last-resort compiler rather than failing the build
+// outright. It must only kick in when the primary binary itself couldn't be
+// found, not when the primary ran and the compile failed.
+const fallbackCompilerEnvVar = "COMPILER_WRAPPER_FALLBACK_COMPILER"
+
+// execWithFallback calls doExec(compilerCmd); if that fails because
+// compilerCmd.Path doesn't exist and COMPILER_WRAPPER_FALLBACK_COMPILER is
+// set, it retries doExec once with the fallback binary substituted in
+// place of Path, keeping the same Args and EnvUpdates. Any other failure
+// (the binary exists but the compile itself errored) is returned as-is,
+// without a fallback attempt.
+func execWithFallback(e env, compilerCmd *command, doExec func(*command) error) error {
+	err := doExec(compilerCmd)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	fallback, ok := e.getenv(fallbackCompilerEnvVar)
+	if !ok || fallback == "" {
+		return err
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Path = fallback
+	return doExec(&newCmd)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestExecWithFallbackRetriesOnNotExist(t *testing.T) {
+	e := newFakeEnv(map[string]string{fallbackCompilerEnvVar: "/usr/bin/fallback-cc"})
+	cmd := &command{Path: "/usr/bin/missing-cc", Args: []string{"-c", "foo.c"}}
+
+	var attempted []string
+	doExec := func(c *command) error {
+		attempted = append(attempted, c.Path)
+		if c.Path == "/usr/bin/missing-cc" {
+			return &os.PathError{Op: "exec", Path: c.Path, Err: os.ErrNotExist}
+		}
+		return nil
+	}
+
+	if err := execWithFallback(e, cmd, doExec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"/usr/bin/missing-cc", "/usr/bin/fallback-cc"}
+	if len(attempted) != len(want) {
+		t.Fatalf("got %v, want %v", attempted, want)
+	}
+	for i := range want {
+		if attempted[i] != want[i] {
+			t.Errorf("attempt %d: got %q, want %q", i, attempted[i], want[i])
+		}
+	}
+}
+
+func TestExecWithFallbackNoFallbackConfigured(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Path: "/usr/bin/missing-cc"}
+
+	calls := 0
+	doExec := func(c *command) error {
+		calls++
+		return &os.PathError{Op: "exec", Path: c.Path, Err: os.ErrNotExist}
+	}
+
+	err := execWithFallback(e, cmd, doExec)
+	if err == nil {
+		t.Fatal("expected the original error without a fallback configured")
+	}
+	if calls != 1 {
+		t.Errorf("expected exactly 1 attempt, got %d", calls)
+	}
+}
+
+func TestExecWithFallbackDoesNotRetryOnCompileError(t *testing.T) {
+	e := newFakeEnv(map[string]string{fallbackCompilerEnvVar: "/usr/bin/fallback-cc"})
+	cmd := &command{Path: "/usr/bin/real-cc"}
+
+	wantErr := errors.New("compile error: undefined reference")
+	calls := 0
+	doExec := func(c *command) error {
+		calls++
+		return wantErr
+	}
+
+	err := execWithFallback(e, cmd, doExec)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("expected no fallback retry on a non-ENOENT error, got %d calls", calls)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// goExec execs compilerCmd using the Go runtime's syscall.Exec, passing an
+// explicit envp built from e's environment (optionally filtered to
+// COMPILER_WRAPPER_ENV_ALLOWLIST) overlaid with compilerCmd.EnvUpdates.
+// This approximates libcExecv's effect on the sandbox's LD_PRELOAD-based
+// interception without requiring cgo, for builds where cgo is undesirable.
+// It only returns on failure.
+func goExec(e env, compilerCmd *command) error {
+	argv := append([]string{compilerCmd.Path}, compilerCmd.Args...)
+	return syscall.Exec(compilerCmd.Path, argv, buildExecEnviron(e, compilerCmd))
+}
+
+// applyEnvUpdates sets or unsets (for an empty value) each variable in
+// updates on the current process's environment.
+func applyEnvUpdates(updates map[string]string) error {
+	for k, v := range updates {
+		if v == "" {
+			if err := os.Unsetenv(k); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := os.Setenv(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+func TestApplyEnvUpdatesSetsVariables(t *testing.T) {
+	const key = "COMPILER_WRAPPER_TEST_VAR"
+	t.Cleanup(func() { os.Unsetenv(key) })
+
+	if err := applyEnvUpdates(map[string]string{key: "value"}); err != nil {
+		t.Fatalf("applyEnvUpdates: %v", err)
+	}
+	if got := os.Getenv(key); got != "value" {
+		t.Errorf("os.Getenv(%q) = %q, want %q", key, got, "value")
+	}
+}
+
+func TestApplyEnvUpdatesUnsetsEmptyValues(t *testing.T) {
+	const key = "COMPILER_WRAPPER_TEST_VAR_UNSET"
+	os.Setenv(key, "present")
+
+	if err := applyEnvUpdates(map[string]string{key: ""}); err != nil {
+		t.Fatalf("applyEnvUpdates: %v", err)
+	}
+	if _, ok := os.LookupEnv(key); ok {
+		t.Errorf("expected %s to be unset", key)
+	}
+}
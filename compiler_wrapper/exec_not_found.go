@@ -0,0 +1,48 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// compilerNotFoundExitCode is the exit code reported when the compiler
+// binary itself can't be found at exec time, matching the shell convention
+// for "command not found" so CI can distinguish it from a compile failure.
+const compilerNotFoundExitCode = 127
+
+// compilerNotFoundError wraps an exec failure caused by a missing compiler
+// binary, carrying a friendly message in place of the raw ENOENT.
+type compilerNotFoundError struct {
+	path string
+	err  error
+}
+
+func (e *compilerNotFoundError) Error() string {
+	return fmt.Sprintf("compiler not found: %s", e.path)
+}
+
+func (e *compilerNotFoundError) Unwrap() error { return e.err }
+
+// wrapExecNotFoundError wraps err as a compilerNotFoundError, with its
+// friendlier message, if err indicates path doesn't exist. Any other error
+// (the binary exists but exec or the compile itself failed) is returned
+// unchanged.
+func wrapExecNotFoundError(path string, err error) error {
+	if err == nil || !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return &compilerNotFoundError{path: path, err: err}
+}
+
+// exitCodeForExecError maps an exec error to the process exit code the
+// wrapper should report for it: compilerNotFoundExitCode for a missing
+// compiler binary, or ok=false for anything else, leaving the caller to
+// fall back to its generic internal-error exit code.
+func exitCodeForExecError(err error) (code int, ok bool) {
+	var notFound *compilerNotFoundError
+	if errors.As(err, &notFound) {
+		return compilerNotFoundExitCode, true
+	}
+	return 0, false
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestWrapExecNotFoundErrorWrapsENOENT(t *testing.T) {
+	raw := &os.PathError{Op: "exec", Path: "/usr/bin/missing-cc", Err: os.ErrNotExist}
+
+	err := wrapExecNotFoundError("/usr/bin/missing-cc", raw)
+
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected wrapped error to still satisfy errors.Is(os.ErrNotExist), got %v", err)
+	}
+	if err.Error() != "compiler not found: /usr/bin/missing-cc" {
+		t.Errorf("got message %q", err.Error())
+	}
+	if code, ok := exitCodeForExecError(err); !ok || code != compilerNotFoundExitCode {
+		t.Errorf("exitCodeForExecError() = (%d, %v), want (%d, true)", code, ok, compilerNotFoundExitCode)
+	}
+}
+
+func TestWrapExecNotFoundErrorLeavesOtherErrorsUnchanged(t *testing.T) {
+	wantErr := errors.New("compile error: undefined reference")
+
+	err := wrapExecNotFoundError("/usr/bin/real-cc", wantErr)
+
+	if err != wantErr {
+		t.Errorf("expected the original error to be returned unchanged, got %v", err)
+	}
+	if _, ok := exitCodeForExecError(err); ok {
+		t.Error("expected exitCodeForExecError to report ok=false for a non-ENOENT error")
+	}
+}
+
+func TestWrapExecNotFoundErrorNilIsNil(t *testing.T) {
+	if err := wrapExecNotFoundError("/usr/bin/cc", nil); err != nil {
+		t.Errorf("expected nil, got %v", err)
+	}
+}
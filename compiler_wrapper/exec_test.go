@@ -0,0 +1,51 @@
+package main
+
+import "testing"
+
+func TestSelectExecStrategyDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := selectExecStrategy(e); got != execStrategyExecv {
+		t.Errorf("selectExecStrategy() = %q, want %q", got, execStrategyExecv)
+	}
+}
+
+func TestSelectExecStrategyHonorsEnv(t *testing.T) {
+	cases := []struct {
+		value string
+		want  execStrategy
+	}{
+		{"execv", execStrategyExecv},
+		{"execve", execStrategyExecve},
+		{"go", execStrategyGo},
+		{"bogus", execStrategyExecv},
+	}
+	for _, tc := range cases {
+		e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_EXEC_STRATEGY": tc.value})
+		if got := selectExecStrategy(e); got != tc.want {
+			t.Errorf("selectExecStrategy(%q) = %q, want %q", tc.value, got, tc.want)
+		}
+	}
+}
+
+func TestMergeEnvUpdates(t *testing.T) {
+	base := []string{"PATH=/bin", "FOO=old"}
+	updates := map[string]string{"FOO": "new", "BAR": "added"}
+
+	got := mergeEnvUpdates(base, updates)
+
+	seen := map[string]string{}
+	for _, kv := range got {
+		for i := 0; i < len(kv); i++ {
+			if kv[i] == '=' {
+				seen[kv[:i]] = kv[i+1:]
+				break
+			}
+		}
+	}
+	if seen["PATH"] != "/bin" || seen["FOO"] != "new" || seen["BAR"] != "added" {
+		t.Errorf("mergeEnvUpdates(...) = %v, want PATH=/bin FOO=new BAR=added", got)
+	}
+	if len(got) != 3 {
+		t.Errorf("expected 3 entries, got %v", got)
+	}
+}
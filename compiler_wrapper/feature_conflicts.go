@@ -0,0 +1,19 @@
+package main
+
+// checkFeatureConflicts reports a userError when two features that can't
+// usefully run in the same invocation are both enabled, naming the actual
+// features involved so the message doesn't mislead callers into thinking a
+// different combination was enabled. FORCE_DISABLE_WERROR retries the build
+// a second time with relaxed warnings; clang-tidy's sub-invocation reads the
+// same command line and would itself re-run (and likely re-warn) on the
+// retry, so the combination isn't supported.
+func checkFeatureConflicts(e env, compilerIsClang bool, compilerCmd *command) error {
+	forceDisableWerror := processForceDisableWerrorFlag(e, compilerIsClang)
+	_, tidyCompileDB := e.getenv(tidyCompileDBDirEnvVar)
+	tidyWanted := tidyCompileDB && shouldRunClangTidy(e, compilerCmd)
+
+	if forceDisableWerror && tidyWanted {
+		return newUserError("FORCE_DISABLE_WERROR is incompatible with TIDY_COMPILE_DB_DIR: the double-build retry would re-run tidy against relaxed warnings")
+	}
+	return nil
+}
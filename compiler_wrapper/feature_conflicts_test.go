@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestCheckFeatureConflictsForceDisableWerrorWithTidyCompileDB(t *testing.T) {
+	e := newFakeEnv(map[string]string{
+		"FORCE_DISABLE_WERROR": "1",
+		tidyCompileDBDirEnvVar: "/build/out",
+	})
+	cmd := &command{Args: []string{"-c", "foo.cc"}}
+
+	err := checkFeatureConflicts(e, true, cmd)
+	if err == nil {
+		t.Fatal("expected a conflict error")
+	}
+	if !isUserError(err) {
+		t.Errorf("expected a userError, got %T", err)
+	}
+}
+
+func TestCheckFeatureConflictsNoConflictWhenOnlyOneEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FORCE_DISABLE_WERROR": "1"})
+	cmd := &command{Args: []string{"-c", "foo.cc"}}
+
+	if err := checkFeatureConflicts(e, true, cmd); err != nil {
+		t.Errorf("expected no conflict, got %v", err)
+	}
+}
+
+func TestCheckFeatureConflictsNoConflictWhenNeitherEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.cc"}}
+
+	if err := checkFeatureConflicts(e, true, cmd); err != nil {
+		t.Errorf("expected no conflict, got %v", err)
+	}
+}
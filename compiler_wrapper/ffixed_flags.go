@@ -0,0 +1,45 @@
+package main
+
+import "strings"
+
+// unsupportedArmFfixedFlags are -ffixed-<reg> flags gcc accepts on arm that
+// clang rejects outright, curated from kernel build failures. Anything not
+// in this set is assumed supported and passed through unchanged.
+var unsupportedArmFfixedFlags = map[string]bool{
+	"-ffixed-r9": true,
+}
+
+// isFfixedFlag reports whether arg is a -ffixed-<reg> flag at all.
+func isFfixedFlag(arg string) bool {
+	return strings.HasPrefix(arg, "-ffixed-")
+}
+
+// filterUnsupportedArmFfixedFlags drops -ffixed-<reg> flags clang doesn't
+// support for arm targets, logging each one under debug, the same way
+// resolveArchCPUConflict handles -march/-mcpu. Non-arm targets and
+// supported -ffixed- flags are left untouched.
+func filterUnsupportedArmFfixedFlags(e env, isArmTarget bool, compilerCmd *command) *command {
+	if !isArmTarget {
+		return compilerCmd
+	}
+
+	var dropped []string
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if isFfixedFlag(arg) && unsupportedArmFfixedFlags[arg] {
+			dropped = append(dropped, arg)
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if len(dropped) == 0 {
+		return compilerCmd
+	}
+
+	for _, arg := range dropped {
+		logDebugf(e, "dropping %q: clang does not support this register on arm", arg)
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,39 @@
+package main
+
+import "testing"
+
+func TestFilterUnsupportedArmFfixedFlagsDropsUnsupported(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-ffixed-r9", "foo.c"}}
+
+	got := filterUnsupportedArmFfixedFlags(e, true, cmd)
+	want := []string{"-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestFilterUnsupportedArmFfixedFlagsKeepsSupported(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-ffixed-r8", "foo.c"}}
+
+	got := filterUnsupportedArmFfixedFlags(e, true, cmd)
+	if got != cmd {
+		t.Error("expected a supported -ffixed- flag to be left alone")
+	}
+}
+
+func TestFilterUnsupportedArmFfixedFlagsNoopForNonArm(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-ffixed-r9", "foo.c"}}
+
+	got := filterUnsupportedArmFfixedFlags(e, false, cmd)
+	if got != cmd {
+		t.Error("expected non-arm targets to be left untouched")
+	}
+}
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// reportForceDisableWerrorSummary writes a one-line operational summary to
+// stderr after a FORCE_DISABLE_WERROR retry, so it's visible at a glance
+// which builds needed it and whether relaxing Werror was enough to get them
+// passing. Gated behind FORCE_DISABLE_WERROR_SUMMARY to keep the default
+// output silent.
+func reportForceDisableWerrorSummary(e env, flags []string, retrySucceeded bool) {
+	if _, ok := e.getenv("FORCE_DISABLE_WERROR_SUMMARY"); !ok {
+		return
+	}
+	status := "failed"
+	if retrySucceeded {
+		status = "succeeded"
+	}
+	fmt.Fprintf(e.stderr(), "werror: suppressed %d warnings via %s, build %s\n",
+		len(flags), strings.Join(flags, " "), status)
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReportForceDisableWerrorSummaryEnabledSuccess(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FORCE_DISABLE_WERROR_SUMMARY": "1"})
+
+	reportForceDisableWerrorSummary(e, []string{"-Wno-error=foo", "-Wno-error=bar"}, true)
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "suppressed 2 warnings via -Wno-error=foo -Wno-error=bar") {
+		t.Errorf("expected summary to list flag count and flags, got: %q", got)
+	}
+	if !strings.Contains(got, "build succeeded") {
+		t.Errorf("expected summary to report success, got: %q", got)
+	}
+}
+
+func TestReportForceDisableWerrorSummaryEnabledFailure(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FORCE_DISABLE_WERROR_SUMMARY": "1"})
+
+	reportForceDisableWerrorSummary(e, []string{"-Wno-error=foo"}, false)
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "build failed") {
+		t.Errorf("expected summary to report failure, got: %q", got)
+	}
+}
+
+func TestReportForceDisableWerrorSummaryQuietByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+
+	reportForceDisableWerrorSummary(e, []string{"-Wno-error=foo"}, true)
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no summary without FORCE_DISABLE_WERROR_SUMMARY, got: %q", e.stderrBuf.String())
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestProcessForceDisableWerrorFlagDefaultsToClangOnly(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FORCE_DISABLE_WERROR": "1"})
+
+	if !processForceDisableWerrorFlag(e, true) {
+		t.Error("expected clang to get double-build werror handling")
+	}
+	if processForceDisableWerrorFlag(e, false) {
+		t.Error("expected gcc to be excluded by default")
+	}
+}
+
+func TestProcessForceDisableWerrorFlagGccOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{
+		"FORCE_DISABLE_WERROR":             "1",
+		"FORCE_DISABLE_WERROR_INCLUDE_GCC": "1",
+	})
+
+	if !processForceDisableWerrorFlag(e, false) {
+		t.Error("expected gcc to get double-build werror handling with the override set")
+	}
+}
+
+func TestProcessForceDisableWerrorFlagOffByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+
+	if processForceDisableWerrorFlag(e, true) {
+		t.Error("expected feature to be off when FORCE_DISABLE_WERROR is unset")
+	}
+}
@@ -0,0 +1,21 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// annotateForceDisableWerrorRetryFailure writes an explanatory note to
+// stderr when a FORCE_DISABLE_WERROR retry (built with extraFlags added)
+// still failed, so the failure isn't silently indistinguishable from an
+// ordinary build failure. It never changes the exit code the wrapper
+// reports; it only adds context. Gated behind FORCE_DISABLE_WERROR_VERBOSE
+// to keep the default output quiet.
+func annotateForceDisableWerrorRetryFailure(e env, extraFlags []string) {
+	if _, ok := e.getenv("FORCE_DISABLE_WERROR_VERBOSE"); !ok {
+		return
+	}
+	fmt.Fprintf(e.stderr(),
+		"crostc: FORCE_DISABLE_WERROR retry with %s did not fix the build\n",
+		strings.Join(extraFlags, " "))
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAnnotateForceDisableWerrorRetryFailureVerbose(t *testing.T) {
+	e := newFakeEnv(map[string]string{"FORCE_DISABLE_WERROR_VERBOSE": "1"})
+
+	const exitCode = 1
+	annotateForceDisableWerrorRetryFailure(e, []string{"-Wno-error=foo", "-Wno-error=bar"})
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "-Wno-error=foo") || !strings.Contains(got, "-Wno-error=bar") {
+		t.Errorf("expected annotation to list the extra flags, got: %q", got)
+	}
+	if exitCode != 1 {
+		t.Error("exit code must be unaffected by the annotation")
+	}
+}
+
+func TestAnnotateForceDisableWerrorRetryFailureQuietByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+
+	annotateForceDisableWerrorRetryFailure(e, []string{"-Wno-error=foo"})
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no annotation without FORCE_DISABLE_WERROR_VERBOSE, got: %q", e.stderrBuf.String())
+	}
+}
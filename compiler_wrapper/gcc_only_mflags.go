@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+)
+
+// gccOnlyMFlagPrefixes are -m flag prefixes that gcc accepts but clang
+// rejects outright, with no clang equivalent worth translating to. They're
+// dropped rather than passed through so a board's gcc-tuned flag set can be
+// reused unmodified when building with clang.
+var gccOnlyMFlagPrefixes = []string{
+	"-mno-movbe",
+	"-mfpmath=",
+}
+
+const preferredStackBoundaryPrefix = "-mpreferred-stack-boundary="
+
+// isGCCOnlyMFlag reports whether arg is one of gccOnlyMFlagPrefixes.
+func isGCCOnlyMFlag(arg string) bool {
+	for _, prefix := range gccOnlyMFlagPrefixes {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// translateStackBoundaryFlag converts gcc's -mpreferred-stack-boundary=<n>
+// (alignment given as a power of two) to clang's -mstack-alignment=<bytes>
+// (alignment given directly in bytes). ok is false if arg isn't a
+// -mpreferred-stack-boundary= flag or its value isn't a valid integer.
+func translateStackBoundaryFlag(arg string) (translated string, ok bool) {
+	value, isBoundary := strings.CutPrefix(arg, preferredStackBoundaryPrefix)
+	if !isBoundary {
+		return "", false
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return "", false
+	}
+	return "-mstack-alignment=" + strconv.Itoa(1<<n), true
+}
+
+// filterGCCOnlyMFlags drops or translates gcc-only -m flags when building
+// with clang, so a board's gcc-tuned -m flag set doesn't make clang invocations
+// fail outright. It's a no-op for gcc, which accepts all of these natively.
+func filterGCCOnlyMFlags(compilerIsClang bool, compilerCmd *command) *command {
+	if !compilerIsClang {
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if translated, ok := translateStackBoundaryFlag(arg); ok {
+			newArgs = append(newArgs, translated)
+			changed = true
+			continue
+		}
+		if isGCCOnlyMFlag(arg) {
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterGCCOnlyMFlagsDropsForClang(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-mno-movbe", "-mfpmath=sse"}}
+
+	got := filterGCCOnlyMFlags(true, cmd)
+
+	want := []string{"-c", "foo.c"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestFilterGCCOnlyMFlagsTranslatesStackBoundary(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-mpreferred-stack-boundary=4"}}
+
+	got := filterGCCOnlyMFlags(true, cmd)
+
+	want := []string{"-c", "foo.c", "-mstack-alignment=16"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestFilterGCCOnlyMFlagsNoopForGCC(t *testing.T) {
+	cmd := &command{Args: []string{"-mno-movbe", "-mpreferred-stack-boundary=4"}}
+
+	got := filterGCCOnlyMFlags(false, cmd)
+
+	if got != cmd {
+		t.Error("expected gcc invocations to be returned unmodified")
+	}
+}
+
+func TestFilterGCCOnlyMFlagsNoopWhenNonepresent(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := filterGCCOnlyMFlags(true, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when no gcc-only -m flags are present")
+	}
+}
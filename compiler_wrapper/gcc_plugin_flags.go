@@ -0,0 +1,33 @@
+package main
+
+import "strings"
+
+// filterGCCPluginFlags drops -fplugin= and its companion -fplugin-arg-*
+// flags when compiling with clang, which doesn't understand gcc plugins and
+// would otherwise hard-error on mixed codebases that annotate some sources
+// for gcc plugins. gcc keeps them untouched.
+func filterGCCPluginFlags(e env, compilerIsClang bool, compilerCmd *command) *command {
+	if !compilerIsClang {
+		return compilerCmd
+	}
+
+	var dropped []string
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-fplugin=") || strings.HasPrefix(arg, "-fplugin-arg-") {
+			dropped = append(dropped, arg)
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if len(dropped) == 0 {
+		return compilerCmd
+	}
+
+	for _, arg := range dropped {
+		logDebugf(e, "dropping gcc plugin flag %q for clang", arg)
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
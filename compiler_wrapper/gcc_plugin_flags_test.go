@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestFilterGCCPluginFlagsDropsOnClang(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-fplugin=myplugin.so", "-fplugin-arg-myplugin-opt=1"}}
+
+	got := filterGCCPluginFlags(e, true, cmd)
+	want := []string{"-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestFilterGCCPluginFlagsKeepsOnGcc(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-fplugin=myplugin.so"}}
+
+	got := filterGCCPluginFlags(e, false, cmd)
+	if got != cmd {
+		t.Errorf("expected gcc command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestFilterGCCPluginFlagsNoopWithoutPluginFlags(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	got := filterGCCPluginFlags(newFakeEnv(map[string]string{}), true, cmd)
+	if got != cmd {
+		t.Errorf("expected command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestFilterGCCPluginFlagsLogsUnderDebug(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-fplugin=myplugin.so"}}
+
+	filterGCCPluginFlags(e, true, cmd)
+
+	if got := e.stderrBuf.String(); got == "" {
+		t.Error("expected a debug log line about the dropped flag")
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "strings"
+
+const includeFlag = "-include"
+const includeFlagPrefix = "-include="
+
+// processIncludeFlag rewrites an absolute path passed to -include (forced
+// header inclusion, both the separated "-include <path>" and combined
+// "-include=<path>" forms) to be relative to cfg.Root, for the same reason
+// processGCCSpecsFlag does: the path ends up on the command line used for
+// remote/reproducible builds too, so leaving it absolute leaks the local
+// build root and hurts cache hit rates across machines with different
+// roots. It's a no-op unless relativization is enabled.
+func processIncludeFlag(cfg *config, compilerCmd *command) *command {
+	if !cfg.RelativizePaths {
+		return compilerCmd
+	}
+
+	newArgs := make([]string, len(compilerCmd.Args))
+	copy(newArgs, compilerCmd.Args)
+	changed := false
+	for i := 0; i < len(newArgs); i++ {
+		arg := newArgs[i]
+		switch {
+		case arg == includeFlag && i+1 < len(newArgs):
+			if rel, ok := rootRelative(cfg.Root, newArgs[i+1]); ok {
+				newArgs[i+1] = rel
+				changed = true
+			}
+			i++
+		default:
+			if path, isCombined := strings.CutPrefix(arg, includeFlagPrefix); isCombined {
+				if rel, ok := rootRelative(cfg.Root, path); ok {
+					newArgs[i] = includeFlagPrefix + rel
+					changed = true
+				}
+			}
+		}
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcessIncludeFlagRewritesSeparatedForm(t *testing.T) {
+	root := t.TempDir()
+	header := filepath.Join(root, "force.h")
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-c", "foo.c", includeFlag, header}}
+
+	got := processIncludeFlag(cfg, cmd)
+
+	want := []string{"-c", "foo.c", includeFlag, "force.h"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessIncludeFlagRewritesCombinedForm(t *testing.T) {
+	root := t.TempDir()
+	header := filepath.Join(root, "force.h")
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-c", "foo.c", includeFlagPrefix + header}}
+
+	got := processIncludeFlag(cfg, cmd)
+
+	want := []string{"-c", "foo.c", includeFlagPrefix + "force.h"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessIncludeFlagOutsideRootPassesThrough(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{includeFlag, "/outside/force.h"}}
+
+	got := processIncludeFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified for a path outside root")
+	}
+}
+
+func TestProcessIncludeFlagNoopWhenDisabled(t *testing.T) {
+	cfg := &config{RelativizePaths: false}
+	cmd := &command{Args: []string{includeFlag, "/some/force.h"}}
+
+	got := processIncludeFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when relativization is disabled")
+	}
+}
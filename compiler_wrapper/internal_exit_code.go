@@ -0,0 +1,38 @@
+package main
+
+import "strconv"
+
+// internalExitCodeEnvVar overrides the process exit code reported for
+// wrapper-internal failures (as opposed to a user invocation error or the
+// compiler's own exit code), so CI can tell "the wrapper broke" apart from
+// "the build broke" at the process level.
+const internalExitCodeEnvVar = "COMPILER_WRAPPER_INTERNAL_EXIT_CODE"
+
+// defaultInternalExitCode is returned for every existing caller that
+// doesn't set COMPILER_WRAPPER_INTERNAL_EXIT_CODE, keeping today's
+// behavior unchanged by default.
+const defaultInternalExitCode = 1
+
+// exitCodeForCompilerErr resolves the process exit code callCompiler
+// should report for compilerErr: compilerNotFoundExitCode for a missing
+// compiler (see exitCodeForExecError), 1 for a userError (an invocation
+// mistake, not a wrapper bug), or defaultInternalExitCode -- overridable
+// via COMPILER_WRAPPER_INTERNAL_EXIT_CODE -- for any other error, which by
+// elimination is a wrapper-internal failure.
+func exitCodeForCompilerErr(e env, compilerErr error) int {
+	if compilerErr == nil {
+		return 0
+	}
+	if code, ok := exitCodeForExecError(compilerErr); ok {
+		return code
+	}
+	if isUserError(compilerErr) {
+		return 1
+	}
+	if v, ok := e.getenv(internalExitCodeEnvVar); ok {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return defaultInternalExitCode
+}
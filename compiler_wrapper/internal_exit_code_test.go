@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExitCodeForCompilerErrDefaultForInternalError(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := exitCodeForCompilerErr(e, errors.New("nil pointer somewhere")); got != defaultInternalExitCode {
+		t.Errorf("got %d, want %d", got, defaultInternalExitCode)
+	}
+}
+
+func TestExitCodeForCompilerErrHonorsOverrideForInternalError(t *testing.T) {
+	e := newFakeEnv(map[string]string{internalExitCodeEnvVar: "70"})
+	if got := exitCodeForCompilerErr(e, errors.New("nil pointer somewhere")); got != 70 {
+		t.Errorf("got %d, want 70", got)
+	}
+}
+
+func TestExitCodeForCompilerErrUserErrorIgnoresOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{internalExitCodeEnvVar: "70"})
+	if got := exitCodeForCompilerErr(e, newUserError("bad flag combination")); got != 1 {
+		t.Errorf("got %d, want 1 for a user error regardless of the override", got)
+	}
+}
+
+func TestExitCodeForCompilerErrNoErrorIsZero(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := exitCodeForCompilerErr(e, nil); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+}
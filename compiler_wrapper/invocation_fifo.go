@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"syscall"
+)
+
+// invocationFIFOEnvVar names a FIFO the resolved compiler invocation is
+// written to as JSON, for live build dashboards tailing it.
+const invocationFIFOEnvVar = "COMPILER_WRAPPER_INVOCATION_FIFO"
+
+// maybeWriteInvocationFIFO writes compilerCmd as JSON to the FIFO named by
+// COMPILER_WRAPPER_INVOCATION_FIFO, if set and if a FIFO actually exists at
+// that path. The open is non-blocking: with no reader attached, it fails
+// immediately and is swallowed rather than stalling the compile waiting for
+// one to show up. It's also a no-op if the var is unset or the path exists
+// but isn't a FIFO.
+func maybeWriteInvocationFIFO(e env, compilerCmd *command) {
+	path, ok := e.getenv(invocationFIFOEnvVar)
+	if !ok || path == "" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil || info.Mode()&os.ModeNamedPipe == 0 {
+		return
+	}
+	data, err := json.Marshal(compilerCmd)
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	f.Write(data)
+}
@@ -0,0 +1,70 @@
+//go:build linux
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestMaybeWriteInvocationFIFOWritesJSON(t *testing.T) {
+	dir := t.TempDir()
+	fifoPath := filepath.Join(dir, "invocations.fifo")
+	if err := syscall.Mkfifo(fifoPath, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	e := newFakeEnv(map[string]string{invocationFIFOEnvVar: fifoPath})
+	cmd := &command{Path: "/usr/bin/gcc", Args: []string{"-c", "foo.c"}}
+
+	// Opening the FIFO read-write keeps a reader attached throughout,
+	// without the open itself blocking on Linux, which removes the need to
+	// race a separate reader goroutine against the non-blocking writer.
+	rw, err := os.OpenFile(fifoPath, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rw.Close()
+
+	maybeWriteInvocationFIFO(e, cmd)
+
+	buf := make([]byte, 4096)
+	n, err := rw.Read(buf)
+	if err != nil {
+		t.Fatalf("failed to read from fifo: %v", err)
+	}
+	var got command
+	if err := json.Unmarshal(buf[:n], &got); err != nil {
+		t.Fatalf("json.Unmarshal: %v", err)
+	}
+	if got.Path != cmd.Path {
+		t.Errorf("Path = %q, want %q", got.Path, cmd.Path)
+	}
+}
+
+func TestMaybeWriteInvocationFIFONoopWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	maybeWriteInvocationFIFO(e, &command{Path: "/usr/bin/gcc"})
+}
+
+func TestMaybeWriteInvocationFIFONoopForRegularFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "not-a-fifo")
+	if err := os.WriteFile(path, []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	e := newFakeEnv(map[string]string{invocationFIFOEnvVar: path})
+
+	maybeWriteInvocationFIFO(e, &command{Path: "/usr/bin/gcc"})
+
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(contents) != 0 {
+		t.Errorf("expected the regular file to be left untouched, got %q", contents)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+// isysrootFlag is clang's macOS-style SDK root flag, the -isysroot
+// analogue of --sysroot for cross builds targeting macOS.
+const isysrootFlag = "-isysroot"
+
+// userIsysroot reports the path passed to a user-supplied -isysroot flag,
+// if any.
+func userIsysroot(compilerCmd *command) (path string, ok bool) {
+	for i, arg := range compilerCmd.Args {
+		if arg == isysrootFlag && i+1 < len(compilerCmd.Args) {
+			return compilerCmd.Args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// processIsysrootFlag handles -isysroot the same way processIncludeFlag
+// and processGCCSpecsFlag handle their own absolute paths: when the user
+// already passed -isysroot, their path is relativized to cfg.Root if
+// RelativizePaths is enabled and it lives under Root, and no default is
+// injected over it. When the user didn't pass one, cfg.DefaultIsysroot (if
+// set) is injected so macOS cross builds always have an explicit SDK root.
+func processIsysrootFlag(cfg *config, compilerCmd *command) *command {
+	if path, ok := userIsysroot(compilerCmd); ok {
+		if !cfg.RelativizePaths {
+			return compilerCmd
+		}
+		rel, ok := rootRelative(cfg.Root, path)
+		if !ok {
+			return compilerCmd
+		}
+		newArgs := make([]string, len(compilerCmd.Args))
+		copy(newArgs, compilerCmd.Args)
+		for i, arg := range newArgs {
+			if arg == isysrootFlag && i+1 < len(newArgs) {
+				newArgs[i+1] = rel
+				break
+			}
+		}
+		newCmd := *compilerCmd
+		newCmd.Args = newArgs
+		return &newCmd
+	}
+
+	if cfg.DefaultIsysroot == "" {
+		return compilerCmd
+	}
+	return addPostUserArgs(compilerCmd, []string{isysrootFlag, cfg.DefaultIsysroot})
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestProcessIsysrootFlagSkipsDefaultWhenUserSupplied(t *testing.T) {
+	cfg := &config{DefaultIsysroot: "/sdk/MacOSX.sdk"}
+	cmd := &command{Args: []string{"-c", "foo.c", isysrootFlag, "/custom/sdk"}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected a user-supplied -isysroot to suppress the default")
+	}
+}
+
+func TestProcessIsysrootFlagInjectsDefault(t *testing.T) {
+	cfg := &config{DefaultIsysroot: "/sdk/MacOSX.sdk"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	want := []string{"-c", "foo.c", isysrootFlag, "/sdk/MacOSX.sdk"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessIsysrootFlagNoopWithoutDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected no change without DefaultIsysroot configured")
+	}
+}
+
+func TestProcessIsysrootFlagRelativizesUnderRoot(t *testing.T) {
+	root := t.TempDir()
+	sdk := filepath.Join(root, "MacOSX.sdk")
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-c", "foo.c", isysrootFlag, sdk}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	want := []string{"-c", "foo.c", isysrootFlag, "MacOSX.sdk"}
+	if !reflect.DeepEqual(got.Args, want) {
+		t.Errorf("Args = %v, want %v", got.Args, want)
+	}
+}
+
+func TestProcessIsysrootFlagLeavesOutsideRootUnchanged(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-c", "foo.c", isysrootFlag, "/outside/MacOSX.sdk"}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified for a path outside root")
+	}
+}
+
+func TestProcessIsysrootFlagLeavesUserPathUnchangedWhenRelativizeDisabled(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c", isysrootFlag, "/custom/sdk"}}
+
+	got := processIsysrootFlag(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when RelativizePaths is disabled")
+	}
+}
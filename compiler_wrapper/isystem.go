@@ -0,0 +1,43 @@
+package main
+
+// addPreUserArgs returns a copy of compilerCmd with preArgs placed before
+// the rest of Args. It's meant for flags, like -isystem, whose relative
+// search priority against the user's own flags is fixed by the flag itself
+// rather than by position, so prepending them doesn't override anything
+// the user passed.
+func addPreUserArgs(compilerCmd *command, preArgs []string) *command {
+	if len(preArgs) == 0 {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, preArgs...), compilerCmd.Args...)
+	return &newCmd
+}
+
+// addPostUserArgs returns a copy of compilerCmd with postArgs appended after
+// the rest of Args, for flags where the rightmost occurrence wins (like -B),
+// so appending always lets the wrapper's choice take priority over an
+// earlier positional one.
+func addPostUserArgs(compilerCmd *command, postArgs []string) *command {
+	if len(postArgs) == 0 {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), postArgs...)
+	return &newCmd
+}
+
+// processExtraIsystemDirs injects cfg.ExtraIsystemDirs as -isystem flags
+// ahead of the user's own arguments. gcc and clang both search -isystem
+// directories after -I/-iquote ones regardless of command-line order, so
+// this can't shadow a user's own headers even though it's prepended.
+func processExtraIsystemDirs(cfg *config, compilerCmd *command) *command {
+	if len(cfg.ExtraIsystemDirs) == 0 {
+		return compilerCmd
+	}
+	preArgs := make([]string, 0, len(cfg.ExtraIsystemDirs)*2)
+	for _, dir := range cfg.ExtraIsystemDirs {
+		preArgs = append(preArgs, "-isystem", dir)
+	}
+	return addPreUserArgs(compilerCmd, preArgs)
+}
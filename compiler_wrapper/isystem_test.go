@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestAddPreUserArgsPrepends(t *testing.T) {
+	cmd := &command{Args: []string{"-I", "./include", "foo.c"}}
+	got := addPreUserArgs(cmd, []string{"-isystem", "/opt/board/include"})
+
+	want := []string{"-isystem", "/opt/board/include", "-I", "./include", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestAddPreUserArgsNoopWithoutArgs(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	if got := addPreUserArgs(cmd, nil); got != cmd {
+		t.Errorf("expected command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestProcessExtraIsystemDirsInjectsBeforeUserArgs(t *testing.T) {
+	cfg := &config{ExtraIsystemDirs: []string{"/opt/board/include", "/opt/board/include2"}}
+	cmd := &command{Args: []string{"-I", "./include", "foo.c"}}
+
+	got := processExtraIsystemDirs(cfg, cmd)
+	want := []string{"-isystem", "/opt/board/include", "-isystem", "/opt/board/include2", "-I", "./include", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessExtraIsystemDirsNoopWhenUnconfigured(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	if got := processExtraIsystemDirs(cfg, cmd); got != cmd {
+		t.Errorf("expected command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestGetConfig(t *testing.T) {
+	cfg, ok := getConfig("arm-embedded-hardened")
+	if !ok || cfg.ConfigName != "arm-embedded-hardened" {
+		t.Errorf("got (%+v, %v)", cfg, ok)
+	}
+	if _, ok := getConfig("does-not-exist"); ok {
+		t.Error("expected an unknown config name to report !ok")
+	}
+}
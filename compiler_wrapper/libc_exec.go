@@ -0,0 +1,94 @@
+//go:build cgo
+
+package main
+
+/*
+#include <unistd.h>
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"fmt"
+	"unsafe"
+)
+
+// newCArgv builds a NULL-terminated argv array for a C exec call: argv[0]
+// is prog, followed by args. The caller must free it with freeCArgv.
+func newCArgv(prog string, args []string) []*C.char {
+	cArgv := make([]*C.char, len(args)+2)
+	cArgv[0] = C.CString(prog)
+	for i, a := range args {
+		cArgv[i+1] = C.CString(a)
+	}
+	cArgv[len(args)+1] = nil
+	return cArgv
+}
+
+func freeCArgv(cArgv []*C.char) {
+	for _, c := range cArgv {
+		if c != nil {
+			C.free(unsafe.Pointer(c))
+		}
+	}
+}
+
+// libcSetenvAll applies updates to the process environment via libc
+// setenv(3), so that LD_PRELOAD-based sandboxing which intercepts libc
+// environment calls observes them.
+func libcSetenvAll(updates map[string]string) error {
+	for k, v := range updates {
+		ck := C.CString(k)
+		cv := C.CString(v)
+		_, err := C.setenv(ck, cv, 1)
+		C.free(unsafe.Pointer(ck))
+		C.free(unsafe.Pointer(cv))
+		if err != nil {
+			return fmt.Errorf("setenv %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// libcExecv execs compilerCmd via libc's execv(3), after applying
+// compilerCmd.EnvUpdates into the process environment with libcSetenvAll.
+// It only returns on failure.
+func libcExecv(compilerCmd *command) error {
+	if err := libcSetenvAll(compilerCmd.EnvUpdates); err != nil {
+		return err
+	}
+	cPath := C.CString(compilerCmd.Path)
+	defer C.free(unsafe.Pointer(cPath))
+	cArgv := newCArgv(compilerCmd.Path, compilerCmd.Args)
+	defer freeCArgv(cArgv)
+
+	if _, err := C.execv(cPath, &cArgv[0]); err != nil {
+		return fmt.Errorf("execv %s: %w", compilerCmd.Path, err)
+	}
+	return nil
+}
+
+// libcExecve execs compilerCmd via libc's execve(3), building an explicit
+// envp from e's environment (optionally filtered to
+// COMPILER_WRAPPER_ENV_ALLOWLIST) overlaid with compilerCmd.EnvUpdates,
+// rather than mutating the process environment first. It only returns on
+// failure.
+func libcExecve(e env, compilerCmd *command) error {
+	cPath := C.CString(compilerCmd.Path)
+	defer C.free(unsafe.Pointer(cPath))
+	cArgv := newCArgv(compilerCmd.Path, compilerCmd.Args)
+	defer freeCArgv(cArgv)
+
+	mergedEnv := buildExecEnviron(e, compilerCmd)
+	cEnvp := make([]*C.char, len(mergedEnv)+1)
+	for i, kv := range mergedEnv {
+		cEnvp[i] = C.CString(kv)
+	}
+	cEnvp[len(mergedEnv)] = nil
+	defer freeCArgv(cEnvp)
+
+	if _, err := C.execve(cPath, &cArgv[0], &cEnvp[0]); err != nil {
+		return fmt.Errorf("execve %s: %w", compilerCmd.Path, err)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+//go:build !cgo
+
+package main
+
+import "fmt"
+
+// libcExecv and libcExecve require cgo; builds without it fall back to an
+// explicit error rather than silently behaving differently. Use
+// execStrategyGo (COMPILER_WRAPPER_EXEC_STRATEGY=go) for cgo-free builds.
+
+func libcExecv(compilerCmd *command) error {
+	return fmt.Errorf("libcExecv: not available in a non-cgo build")
+}
+
+func libcExecve(e env, compilerCmd *command) error {
+	return fmt.Errorf("libcExecve: not available in a non-cgo build")
+}
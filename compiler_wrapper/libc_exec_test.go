@@ -0,0 +1,81 @@
+//go:build cgo
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestLibcExecvPropagatesEnvUpdates and TestLibcExecvePropagatesEnvUpdates
+// drive the real libc exec paths in a subprocess, since a successful exec
+// replaces the calling process and can't be observed in-process.
+func TestLibcExecvPropagatesEnvUpdates(t *testing.T) {
+	out := runExecHelper(t, "execv")
+	if !strings.Contains(out, "GREETING=hello") {
+		t.Errorf("expected child environment to contain the update, got: %q", out)
+	}
+}
+
+func TestLibcExecvePropagatesEnvUpdates(t *testing.T) {
+	out := runExecHelper(t, "execve")
+	if !strings.Contains(out, "GREETING=hello") {
+		t.Errorf("expected child environment to contain the update, got: %q", out)
+	}
+}
+
+func TestGoExecPropagatesEnvUpdates(t *testing.T) {
+	out := runExecHelper(t, "go")
+	if !strings.Contains(out, "GREETING=hello") {
+		t.Errorf("expected child environment to contain the update, got: %q", out)
+	}
+}
+
+func runExecHelper(t *testing.T, strategy string) string {
+	t.Helper()
+	if _, err := exec.LookPath("env"); err != nil {
+		t.Skip("env(1) not available")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run=TestExecHelperProcess")
+	cmd.Env = append(os.Environ(),
+		"GO_WANT_EXEC_HELPER_PROCESS=1",
+		"GO_EXEC_HELPER_STRATEGY="+strategy,
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("helper process failed: %v\noutput: %s", err, out)
+	}
+	return string(out)
+}
+
+// TestExecHelperProcess isn't a real test: it's invoked as a subprocess by
+// runExecHelper, which execs "env" (as a stand-in for the real compiler) so
+// the parent can check that EnvUpdates landed in the child's environment.
+func TestExecHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_EXEC_HELPER_PROCESS") != "1" {
+		t.Skip("not invoked as a helper process")
+	}
+	envPath, err := exec.LookPath("env")
+	if err != nil {
+		t.Fatalf("env(1) not found: %v", err)
+	}
+	cmd := &command{
+		Path:       envPath,
+		EnvUpdates: map[string]string{"GREETING": "hello"},
+	}
+	e := newProcessEnv()
+	switch os.Getenv("GO_EXEC_HELPER_STRATEGY") {
+	case "execv":
+		err = libcExecv(cmd)
+	case "execve":
+		err = libcExecve(e, cmd)
+	case "go":
+		err = goExec(e, cmd)
+	default:
+		t.Fatalf("unknown strategy %q", os.Getenv("GO_EXEC_HELPER_STRATEGY"))
+	}
+	// A successful exec never returns.
+	t.Fatalf("exec failed: %v", err)
+}
@@ -0,0 +1,13 @@
+package main
+
+// isLinkOnly reports whether compilerCmd performs a link rather than a
+// compile: it's neither precompiling (-E), assembling-to-asm (-S), nor
+// compiling-without-linking (-c).
+func isLinkOnly(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == "-c" || arg == "-S" || arg == "-E" {
+			return false
+		}
+	}
+	return true
+}
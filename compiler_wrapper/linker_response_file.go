@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const linkerResponseFilePrefix = "-Wl,@"
+
+// processLinkerResponseFiles rewrites absolute object/library paths inside
+// any -Wl,@file linker response file to be relative to cfg.Root, for the
+// same reason processGCCSpecsFlag rewrites -specs=: a response file's
+// content ends up on the remote/reproducible build's command line too, so
+// leaking the local build root through it defeats the purpose of
+// relativizing the rest of the invocation. The original response file is
+// left untouched; a normalized copy is written to a temp file and the
+// argument is rewritten to point at it. It's a no-op unless relativization
+// is enabled.
+func processLinkerResponseFiles(cfg *config, compilerCmd *command) (*command, error) {
+	if !cfg.RelativizePaths {
+		return compilerCmd, nil
+	}
+
+	newArgs := make([]string, len(compilerCmd.Args))
+	copy(newArgs, compilerCmd.Args)
+	changed := false
+	for i, arg := range newArgs {
+		respPath, isResp := strings.CutPrefix(arg, linkerResponseFilePrefix)
+		if !isResp {
+			continue
+		}
+		contents, err := os.ReadFile(respPath)
+		if err != nil {
+			return nil, fmt.Errorf("linker response file %q: %w", respPath, err)
+		}
+		normalized := rewriteResponseFileContents(cfg.Root, string(contents))
+
+		tmp, err := os.CreateTemp("", "compiler_wrapper_response_*")
+		if err != nil {
+			return nil, fmt.Errorf("linker response file %q: %w", respPath, err)
+		}
+		if _, err := tmp.WriteString(normalized); err != nil {
+			tmp.Close()
+			return nil, fmt.Errorf("linker response file %q: %w", respPath, err)
+		}
+		if err := tmp.Close(); err != nil {
+			return nil, fmt.Errorf("linker response file %q: %w", respPath, err)
+		}
+
+		newArgs[i] = linkerResponseFilePrefix + tmp.Name()
+		changed = true
+	}
+
+	if !changed {
+		return compilerCmd, nil
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd, nil
+}
+
+// rewriteResponseFileContents rewrites every whitespace-separated field of a
+// linker response file that's an absolute path under root to be relative to
+// root, leaving every other field (flags, paths outside root) unchanged.
+func rewriteResponseFileContents(root, contents string) string {
+	fields := strings.Fields(contents)
+	for i, field := range fields {
+		if rel, ok := rootRelative(root, field); ok {
+			fields[i] = rel
+		}
+	}
+	return strings.Join(fields, "\n")
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProcessLinkerResponseFilesRewritesAbsolutePaths(t *testing.T) {
+	root := t.TempDir()
+	objPath := filepath.Join(root, "foo.o")
+	respPath := filepath.Join(root, "response.txt")
+	if err := os.WriteFile(respPath, []byte(objPath+" -lm"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-o", "out", linkerResponseFilePrefix + respPath}}
+
+	got, err := processLinkerResponseFiles(cfg, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rewrittenArg := got.Args[2]
+	newPath, ok := strings.CutPrefix(rewrittenArg, linkerResponseFilePrefix)
+	if !ok {
+		t.Fatalf("expected a rewritten -Wl,@ argument, got %q", rewrittenArg)
+	}
+	defer os.Remove(newPath)
+
+	contents, err := os.ReadFile(newPath)
+	if err != nil {
+		t.Fatalf("failed to read rewritten response file: %v", err)
+	}
+	if strings.Contains(string(contents), objPath) {
+		t.Errorf("expected absolute path to be rewritten, got %q", contents)
+	}
+	if !strings.Contains(string(contents), "foo.o") {
+		t.Errorf("expected relative foo.o in rewritten response file, got %q", contents)
+	}
+	if !strings.Contains(string(contents), "-lm") {
+		t.Errorf("expected -lm to survive rewriting, got %q", contents)
+	}
+
+	origContents, err := os.ReadFile(respPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(origContents) != objPath+" -lm" {
+		t.Error("expected the original response file to be left untouched")
+	}
+}
+
+func TestProcessLinkerResponseFilesNoopWhenDisabled(t *testing.T) {
+	cfg := &config{RelativizePaths: false}
+	cmd := &command{Args: []string{linkerResponseFilePrefix + "/some/response.txt"}}
+
+	got, err := processLinkerResponseFiles(cfg, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when relativization is disabled")
+	}
+}
+
+func TestProcessLinkerResponseFilesMissingFileErrors(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{linkerResponseFilePrefix + filepath.Join(root, "missing.txt")}}
+
+	if _, err := processLinkerResponseFiles(cfg, cmd); err == nil {
+		t.Fatal("expected an error for a missing response file")
+	}
+}
+
+func TestRewriteResponseFileContentsLeavesOutsidePathsAlone(t *testing.T) {
+	root := t.TempDir()
+	contents := "/outside/bar.o -lm"
+	got := rewriteResponseFileContents(root, contents)
+	if got != "/outside/bar.o\n-lm" {
+		t.Errorf("rewriteResponseFileContents(...) = %q, want fields joined unchanged", got)
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import "strings"
+
+// llvmNextArchesEnvVar scopes llvm-next to a comma-separated list of
+// architectures (as returned by archFromTriple), for experiments that
+// shouldn't roll out to every arch a config covers at once. Unset means
+// every arch UseLlvmNext applies to gets the flags, matching the
+// historical all-targets behavior.
+const llvmNextArchesEnvVar = "LLVM_NEXT_ARCHES"
+
+// llvmNextAppliesToArch reports whether llvm-next should apply to arch:
+// always true if LLVM_NEXT_ARCHES is unset, otherwise only if arch is one
+// of its comma-separated entries.
+func llvmNextAppliesToArch(e env, arch string) bool {
+	v, ok := e.getenv(llvmNextArchesEnvVar)
+	if !ok || v == "" {
+		return true
+	}
+	for _, entry := range strings.Split(v, ",") {
+		if strings.TrimSpace(entry) == arch {
+			return true
+		}
+	}
+	return false
+}
+
+// processLlvmNextFlags appends cfg.LlvmNextFlags for clang invocations
+// when cfg.UseLlvmNext is set, letting a config evaluate an upcoming
+// compiler ahead of its promotion to the default without gcc ever seeing
+// clang-next-specific flags. LLVM_NEXT_ARCHES further scopes this to a
+// subset of architectures, derived from cfg.Target, when set.
+func processLlvmNextFlags(e env, cfg *config, compilerIsClang bool, compilerCmd *command) *command {
+	if !cfg.UseLlvmNext || !compilerIsClang || len(cfg.LlvmNextFlags) == 0 {
+		return compilerCmd
+	}
+	if !llvmNextAppliesToArch(e, archFromTriple(cfg.Target)) {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), cfg.LlvmNextFlags...)
+	return &newCmd
+}
@@ -0,0 +1,70 @@
+package main
+
+import "testing"
+
+func TestProcessLlvmNextFlagsAppendsForClang(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{UseLlvmNext: true, LlvmNextFlags: []string{"-Wno-new-warning"}, Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLlvmNextFlags(e, cfg, true, cmd)
+
+	if got.Args[len(got.Args)-1] != "-Wno-new-warning" {
+		t.Errorf("got %v, want -Wno-new-warning appended", got.Args)
+	}
+}
+
+func TestProcessLlvmNextFlagsNoopForGcc(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{UseLlvmNext: true, LlvmNextFlags: []string{"-Wno-new-warning"}, Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLlvmNextFlags(e, cfg, false, cmd)
+
+	if got != cmd {
+		t.Error("expected no change for gcc")
+	}
+}
+
+func TestProcessLlvmNextFlagsNoopWhenDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{LlvmNextFlags: []string{"-Wno-new-warning"}, Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLlvmNextFlags(e, cfg, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when UseLlvmNext is unset")
+	}
+}
+
+func TestProcessLlvmNextFlagsScopedToMatchingArch(t *testing.T) {
+	e := newFakeEnv(map[string]string{llvmNextArchesEnvVar: "armv7a,x86_64"})
+	cfg := &config{UseLlvmNext: true, LlvmNextFlags: []string{"-Wno-new-warning"}, Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLlvmNextFlags(e, cfg, true, cmd)
+
+	if got.Args[len(got.Args)-1] != "-Wno-new-warning" {
+		t.Errorf("got %v, want -Wno-new-warning appended for a matching arch", got.Args)
+	}
+}
+
+func TestProcessLlvmNextFlagsScopedToNonMatchingArch(t *testing.T) {
+	e := newFakeEnv(map[string]string{llvmNextArchesEnvVar: "x86_64"})
+	cfg := &config{UseLlvmNext: true, LlvmNextFlags: []string{"-Wno-new-warning"}, Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLlvmNextFlags(e, cfg, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change for an arch not listed in LLVM_NEXT_ARCHES")
+	}
+}
+
+func TestLlvmNextAppliesToArchDefaultUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if !llvmNextAppliesToArch(e, "armv7a") {
+		t.Error("expected every arch to apply when LLVM_NEXT_ARCHES is unset")
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import "fmt"
+
+// logConfigEnvVar opts into logging the selected config and llvm-next
+// state to stderr once per invocation, for fleet debugging where it's
+// otherwise hard to tell which wrapper configuration a given build
+// actually ran with.
+const logConfigEnvVar = "COMPILER_WRAPPER_LOG_CONFIG"
+
+// logConfigEnabled reports whether COMPILER_WRAPPER_LOG_CONFIG=1 is set.
+func logConfigEnabled(e env) bool {
+	v, ok := e.getenv(logConfigEnvVar)
+	return ok && v == "1"
+}
+
+// maybeLogConfig writes a line to e's stderr naming cfg.ConfigName and
+// whether llvm-next flags were applied to this invocation (llvmNextApplied),
+// when COMPILER_WRAPPER_LOG_CONFIG=1 is set. It's silent otherwise.
+func maybeLogConfig(e env, cfg *config, llvmNextApplied bool) {
+	if !logConfigEnabled(e) {
+		return
+	}
+	fmt.Fprintf(e.stderr(), "wrapper: config=%s llvm-next=%t\n", cfg.ConfigName, llvmNextApplied)
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaybeLogConfigEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{logConfigEnvVar: "1"})
+	cfg := &config{ConfigName: "arm-embedded-hardened"}
+
+	maybeLogConfig(e, cfg, true)
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "config=arm-embedded-hardened") || !strings.Contains(got, "llvm-next=true") {
+		t.Errorf("got %q, want it to mention the config name and llvm-next state", got)
+	}
+}
+
+func TestMaybeLogConfigDisabledByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{ConfigName: "arm-embedded-hardened"}
+
+	maybeLogConfig(e, cfg, false)
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no output by default, got %q", e.stderrBuf.String())
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import "strings"
+
+// normalizeLTOFlag rewrites -flto= values that only make sense for one
+// compiler into the generic -flto that both accept, so LTO invocations
+// built against gcc's conventions still work when pointed at clang, and
+// vice versa. gcc doesn't understand -flto=thin (clang-only); clang
+// doesn't understand -flto=jobserver or -flto=auto (gcc-isms).
+func normalizeLTOFlag(compilerIsClang bool, compilerCmd *command) *command {
+	changed := false
+	newArgs := make([]string, len(compilerCmd.Args))
+	for i, arg := range compilerCmd.Args {
+		newArgs[i] = arg
+		if compilerIsClang {
+			if arg == "-flto=jobserver" || arg == "-flto=auto" {
+				newArgs[i] = "-flto"
+				changed = true
+			}
+		} else {
+			if arg == "-flto=thin" {
+				newArgs[i] = "-flto"
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// ltoModeEnvVar globally overrides the LTO mode (thin vs full) for clang
+// builds, for A/B comparisons that want to flip every LTO build at once
+// without touching individual ebuilds.
+const ltoModeEnvVar = "COMPILER_WRAPPER_LTO_MODE"
+
+// processLTOMode rewrites -flto/-flto=thin to whichever mode
+// COMPILER_WRAPPER_LTO_MODE selects ("thin" or "full"), for clang builds
+// that have an LTO flag at all. It's a no-op for gcc (which has no thin
+// LTO to switch between), when the env var is unset or has an unrecognized
+// value, or when the command has no LTO flag to rewrite.
+func processLTOMode(e env, compilerIsClang bool, compilerCmd *command) *command {
+	if !compilerIsClang {
+		return compilerCmd
+	}
+	mode, ok := e.getenv(ltoModeEnvVar)
+	if !ok {
+		return compilerCmd
+	}
+
+	var want string
+	switch mode {
+	case "thin":
+		want = "-flto=thin"
+	case "full":
+		want = "-flto"
+	default:
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, len(compilerCmd.Args))
+	for i, arg := range compilerCmd.Args {
+		newArgs[i] = arg
+		if (arg == "-flto" || arg == "-flto=thin") && arg != want {
+			newArgs[i] = want
+			changed = true
+		}
+	}
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// stripLTOFlagsForConfigureStage drops every -flto/-flto=<value> argument
+// when isInConfigureStage(e), since LTO during Portage's src_configure
+// wastes time on throwaway probes and can even make some autoconf feature
+// tests fail in ways they wouldn't during the real build. LTO is left
+// untouched for every other phase.
+func stripLTOFlagsForConfigureStage(e env, compilerCmd *command) *command {
+	if !isInConfigureStage(e) {
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg == "-flto" || strings.HasPrefix(arg, "-flto=") {
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
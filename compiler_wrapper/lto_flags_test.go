@@ -0,0 +1,137 @@
+package main
+
+import "testing"
+
+func TestNormalizeLTOFlagGccIsms(t *testing.T) {
+	for _, in := range []string{"-flto=jobserver", "-flto=auto"} {
+		cmd := &command{Args: []string{"-c", "foo.c", in}}
+		got := normalizeLTOFlag(true, cmd)
+		if got.Args[2] != "-flto" {
+			t.Errorf("clang: %s got %v", in, got.Args)
+		}
+	}
+}
+
+func TestNormalizeLTOFlagClangIsm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto=thin"}}
+	got := normalizeLTOFlag(false, cmd)
+	if got.Args[2] != "-flto" {
+		t.Errorf("gcc: got %v", got.Args)
+	}
+}
+
+func TestNormalizeLTOFlagNoopWithoutLTOFlag(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	got := normalizeLTOFlag(true, cmd)
+	if got != cmd {
+		t.Errorf("expected no change without an -flto flag, got %v", got.Args)
+	}
+}
+
+func TestNormalizeLTOFlagNoopWhenAlreadyCompatible(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto=thin"}}
+	got := normalizeLTOFlag(true, cmd)
+	if got != cmd {
+		t.Errorf("expected clang to accept -flto=thin unchanged, got %v", got.Args)
+	}
+
+	cmd2 := &command{Args: []string{"-c", "foo.c", "-flto=auto"}}
+	got2 := normalizeLTOFlag(false, cmd2)
+	if got2 != cmd2 {
+		t.Errorf("expected gcc to accept -flto=auto unchanged, got %v", got2.Args)
+	}
+}
+
+func TestStripLTOFlagsForConfigureStageDropsLTO(t *testing.T) {
+	e := newFakeEnv(map[string]string{"EBUILD_PHASE": "configure"})
+	cmd := &command{Args: []string{"-c", "conftest.c", "-flto", "-flto=thin"}}
+
+	got := stripLTOFlagsForConfigureStage(e, cmd)
+
+	want := []string{"-c", "conftest.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", got.Args, want)
+	}
+	for i, arg := range want {
+		if got.Args[i] != arg {
+			t.Errorf("Args[%d] = %q, want %q", i, got.Args[i], arg)
+		}
+	}
+}
+
+func TestStripLTOFlagsForConfigureStageKeepsLTOOutsideConfigure(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto"}}
+
+	got := stripLTOFlagsForConfigureStage(e, cmd)
+
+	if got != cmd {
+		t.Error("expected -flto to survive outside the configure stage")
+	}
+}
+
+func TestProcessLTOModeThinToFull(t *testing.T) {
+	e := newFakeEnv(map[string]string{ltoModeEnvVar: "full"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto=thin"}}
+
+	got := processLTOMode(e, true, cmd)
+
+	if got.Args[2] != "-flto" {
+		t.Errorf("got %v, want -flto at index 2", got.Args)
+	}
+}
+
+func TestProcessLTOModeFullToThin(t *testing.T) {
+	e := newFakeEnv(map[string]string{ltoModeEnvVar: "thin"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto"}}
+
+	got := processLTOMode(e, true, cmd)
+
+	if got.Args[2] != "-flto=thin" {
+		t.Errorf("got %v, want -flto=thin at index 2", got.Args)
+	}
+}
+
+func TestProcessLTOModeNoopWithoutLTOFlag(t *testing.T) {
+	e := newFakeEnv(map[string]string{ltoModeEnvVar: "full"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processLTOMode(e, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change without an -flto flag")
+	}
+}
+
+func TestProcessLTOModeNoopWithoutEnvVar(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto=thin"}}
+
+	got := processLTOMode(e, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when COMPILER_WRAPPER_LTO_MODE is unset")
+	}
+}
+
+func TestProcessLTOModeNoopForGcc(t *testing.T) {
+	e := newFakeEnv(map[string]string{ltoModeEnvVar: "full"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto=thin"}}
+
+	got := processLTOMode(e, false, cmd)
+
+	if got != cmd {
+		t.Error("expected no change for gcc")
+	}
+}
+
+func TestProcessLTOModeNoopForUnrecognizedValue(t *testing.T) {
+	e := newFakeEnv(map[string]string{ltoModeEnvVar: "bogus"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-flto"}}
+
+	got := processLTOMode(e, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no change for an unrecognized mode value")
+	}
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// wrapperConfigEnvVar selects which of knownConfigs this invocation should
+// use. A given wrapper install (one per board/compiler symlink) has this
+// baked into its environment at setup time.
+const wrapperConfigEnvVar = "COMPILER_WRAPPER_CONFIG"
+
+func main() {
+	os.Exit(callCompiler(newProcessEnv(), os.Args))
+}
+
+// resolveConfig looks up the config named by COMPILER_WRAPPER_CONFIG,
+// falling back to an all-defaults config (ConfigName set, everything else
+// permissive) when it's unset or doesn't match a knownConfigs entry, so an
+// install with no board-specific behavior configured still compiles
+// instead of refusing to run.
+func resolveConfig(e env) *config {
+	name, ok := e.getenv(wrapperConfigEnvVar)
+	if !ok {
+		return &config{}
+	}
+	if cfg, ok := getConfig(name); ok {
+		return cfg
+	}
+	return &config{ConfigName: name}
+}
+
+// callCompiler is the wrapper's entry point: it turns argv into the real
+// compiler invocation and execs it, returning the process exit code (exec
+// only returns at all on failure). argv is os.Args: argv[0] is the path
+// the wrapper was invoked as, which doubles as the real compiler's path
+// since installing the wrapper means renaming the real compiler binary
+// aside and putting the wrapper in its place; argv[1:] are the compiler's
+// own arguments.
+//
+// ccache/bisect delegation, clang-tidy's sub-invocation, and the
+// determinism check are all wired in: the first two rewrite finalCmd before
+// the one real exec, and the latter two run their own independent
+// sub-invocations of the compiler (clang-tidy as a side process, the
+// determinism check via its own capture-and-compare run) without touching
+// the main exec path. What's still unwired is the double-build Werror
+// retry and the old-wrapper comparison: both need to run the real compile,
+// inspect its output, and decide whether to run it again with different
+// flags before reporting a result -- which is incompatible with ending in
+// a process-replacing exec, the way the normal compile path here does. See
+// force_disable_werror.go and wrapper_diff.go for why those are deferred
+// rather than wired in as-is.
+func callCompiler(e env, argv []string) int {
+	start := time.Now()
+	compilerCmd := &command{Path: argv[0], Args: append([]string{}, argv[1:]...)}
+	if resolved, err := resolveAbsCompilerPath(compilerCmd.Path); err == nil {
+		compilerCmd.Path = resolved
+	}
+
+	if isWrapperSelftestCommand(compilerCmd) {
+		report, ok := runWrapperSelftest()
+		fmt.Fprint(e.stderr(), report)
+		if !ok {
+			return 1
+		}
+		return 0
+	}
+
+	if dir, ok := isCheckGoldensCommand(compilerCmd); ok {
+		report, ok := runCheckGoldens(dir)
+		fmt.Fprint(e.stderr(), report)
+		if !ok {
+			return 1
+		}
+		return 0
+	}
+
+	cfg := resolveConfig(e)
+	maybeWarnOldWrapperPath(e, cfg)
+
+	if isPrintArtifactsDirCommand(compilerCmd) {
+		fmt.Fprint(e.stderr(), runPrintArtifactsDir(e, cfg))
+		return 0
+	}
+	if isClangdFlagsCommand(compilerCmd) {
+		fmt.Fprint(e.stderr(), runPrintClangdFlags(cfg, compilerCmd))
+		return 0
+	}
+	if isDumpIncludePathsCommand(compilerCmd) {
+		out, err := dumpIncludePaths(compilerCmd)
+		if err != nil {
+			fmt.Fprintln(e.stderr(), err)
+			return 1
+		}
+		fmt.Fprintln(e.stderr(), string(out))
+		return 0
+	}
+
+	maybeDumpParents(e, os.Getpid())
+	if chain, _, err := collectAllParentProcesses(e, os.Getppid()); err == nil {
+		warnIfChainedWrapper(e, chain)
+	}
+
+	if isCompilerQueryCommand(compilerCmd) {
+		return execCompiler(e, compilerCmd)
+	}
+
+	compilerIsClang := compilerIsClang(e, compilerCmd.Path)
+	finalCmd, err := buildCompilerCommand(e, cfg, compilerIsClang, compilerCmd)
+	if err != nil {
+		fmt.Fprintln(e.stderr(), err)
+		code := exitCodeForCompilerErr(e, err)
+		// This is the only outcome callCompiler itself ever observes: every
+		// other path ends by exec'ing the real compiler, which replaces the
+		// wrapper process before it could report that compile's own
+		// duration or exit code.
+		reportMetric(e, metric{Duration: time.Since(start), ExitCode: code, CompilerKind: compilerKindMetric(compilerIsClang)})
+		return code
+	}
+
+	maybeRunClangTidy(e, finalCmd)
+	maybeCheckCompilerDeterminism(e, finalCmd)
+
+	llvmNextApplied := cfg.UseLlvmNext && compilerIsClang && llvmNextAppliesToArch(e, archFromTriple(cfg.Target))
+	maybeLogConfig(e, cfg, llvmNextApplied)
+	maybeWriteInvocationFIFO(e, finalCmd)
+	if cwd, err := os.Getwd(); err == nil {
+		if output, ok := outputPath(finalCmd); ok {
+			maybeSaveInvocation(e, cwd, output, finalCmd)
+		}
+	}
+
+	useCCache, ccacheReason := ccacheDecision(e, cfg.UseCCache, finalCmd)
+	explainCCacheDecision(e, useCCache, ccacheReason)
+	finalCmd = processCCacheStats(e, useCCache, finalCmd)
+	if useCCache {
+		finalCmd = wrapWithCCache(finalCmd)
+	}
+
+	if bisectCmd, ok := calcBisectCommand(e, finalCmd); ok {
+		finalCmd = bisectCmd
+	}
+
+	exitCode := 0
+	withOutputLock(e, finalCmd, func() error {
+		exitCode = execCompiler(e, finalCmd)
+		return nil
+	})
+	return exitCode
+}
+
+// execCompiler execs compilerCmd, replacing the wrapper process, and
+// returns the exit code to report if that fails. It never returns at all
+// on success.
+func execCompiler(e env, compilerCmd *command) int {
+	if err := (processEnv{}).exec(e, compilerCmd); err != nil {
+		fmt.Fprintln(e.stderr(), err)
+		return exitCodeForCompilerErr(e, err)
+	}
+	return 0
+}
+
+// buildCompilerCommand runs compilerCmd through the wrapper's flag
+// pipeline and validations, in the order their own interactions require
+// (e.g. target derivation before the EABI-dependent PIE check; the
+// GCC-only/plugin/ffixed filters, which can drop user flags, before the
+// dropped-flag warning that reports on them).
+func buildCompilerCommand(e env, cfg *config, compilerIsClang bool, compilerCmd *command) (*command, error) {
+	cmd, err := guardAgainstRecursion(e, compilerCmd)
+	if err != nil {
+		return nil, err
+	}
+	cmd = applyWrapperConsumedFlags(cmd)
+
+	target := builderTarget(cfg)
+	isArmTarget := strings.HasPrefix(archFromTriple(target), "arm")
+	isEabiTarget := isEabiTriple(target)
+
+	cmd = processForceNoExceptions(cfg, cmd)
+	cmd = processForceNoCommon(cfg, cmd)
+	cmd = processDebugStrip(e, cmd)
+	if cwd, err := os.Getwd(); err == nil {
+		cmd = processDebugPrefixMap(cfg, compilerIsClang, cwd, cmd)
+	}
+	cmd = processExtraIsystemDirs(cfg, cmd)
+	cmd = processIncludeFlag(cfg, cmd)
+	cmd = processIsysrootFlag(cfg, cmd)
+	if compilerIsClang {
+		cmd = processClangFlags(target, cmd)
+		cmd = processClangLinkerPath(cfg, cmd)
+		cmd = maybeInjectResourceDir(e, cfg, target, cmd)
+	}
+	cmd = processDefaultMCPU(cfg, cmd)
+	cmd = processDefaultOptLevel(cfg, cmd)
+	cmd = resolveArchCPUConflict(e, isArmTarget, cmd)
+	cmd = processPieFlags(isEabiTarget, cmd)
+	cmd = processInjectBuildID(cfg, cmd)
+	cmd = processInjectFullRelro(cfg, cmd)
+	cmd = processRandomSeed(cfg, cmd)
+	cmd = processLlvmNextFlags(e, cfg, compilerIsClang, cmd)
+	cmd = processPromoteWerror(e, cmd)
+	cmd = processDowngradeWerrorMarker(cmd)
+	cmd = processDedupDefines(e, cmd)
+	cmd = processCompileOnlyFlags(e, cfg, cmd)
+	cmd = processAndroidEmbedBitcode(cfg, cmd)
+	cmd = processDiagnosticsAbsolutePaths(cfg, cmd)
+	cmd = processExportTarget(e, cfg, cmd)
+	cmd = processOptRecordPath(e, cfg, cmd)
+	cmd = normalizeLTOFlag(compilerIsClang, cmd)
+	cmd = processLTOMode(e, compilerIsClang, cmd)
+	if isInConfigureStage(e) {
+		cmd = stripLTOFlagsForConfigureStage(e, cmd)
+	}
+
+	before := len(cmd.Args)
+	cmd = processXclangOnlyFlags(compilerIsClang, cmd)
+	cmd = filterUnsupportedArmFfixedFlags(e, isArmTarget, cmd)
+	cmd = filterGCCOnlyMFlags(compilerIsClang, cmd)
+	cmd = filterWpGroupGCCOnlyMFlags(compilerIsClang, cmd)
+	cmd = filterGCCPluginFlags(e, compilerIsClang, cmd)
+	droppedFlags := len(cmd.Args) < before
+	cmd = processSilenceDropped(e, compilerIsClang, droppedFlags, cmd)
+
+	cmd = injectQunusedArguments(e, compilerIsClang, cmd)
+	cmd = processSanitizerFlags(cmd)
+	cmd = processSanitizerLinkFlags(cmd)
+
+	cmd, err = processGCCSpecsFlag(cfg, !compilerIsClang, cmd)
+	if err != nil {
+		return nil, err
+	}
+	cmd, err = processLinkerResponseFiles(cfg, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkStdFlag(e, false, cmd); err != nil {
+		return nil, err
+	}
+	if err := checkOutputRoot(e, cmd); err != nil {
+		return nil, err
+	}
+	if err := checkFeatureConflicts(e, compilerIsClang, cmd); err != nil {
+		return nil, err
+	}
+
+	return cmd, nil
+}
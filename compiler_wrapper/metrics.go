@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// metric is a compact summary of one wrapper invocation, exported for
+// fleet-wide build profiling.
+type metric struct {
+	Duration     time.Duration
+	ExitCode     int
+	CompilerKind string
+	CacheHit     bool
+}
+
+// metricsSocketPath returns the Unix datagram socket path configured via
+// COMPILER_WRAPPER_METRICS_SOCKET, and whether metrics export is enabled at
+// all.
+func metricsSocketPath(e env) (string, bool) {
+	path, ok := e.getenv("COMPILER_WRAPPER_METRICS_SOCKET")
+	return path, ok && path != ""
+}
+
+// encodeMetric renders m as a compact, parseable datagram payload:
+// "duration_ms=<n> exit_code=<n> compiler=<kind> cache_hit=<bool>".
+func encodeMetric(m metric) []byte {
+	return []byte(fmt.Sprintf("duration_ms=%d exit_code=%d compiler=%s cache_hit=%t",
+		m.Duration.Milliseconds(), m.ExitCode, m.CompilerKind, m.CacheHit))
+}
+
+// compilerKindMetric names m.CompilerKind for a compile, given whether the
+// real compiler is clang.
+func compilerKindMetric(compilerIsClang bool) string {
+	if compilerIsClang {
+		return "clang"
+	}
+	return "gcc"
+}
+
+// reportMetric sends m to the Unix datagram socket named by
+// COMPILER_WRAPPER_METRICS_SOCKET, if set. It's fire-and-forget: dialing,
+// writing, and any error from either are all best-effort, since a metrics
+// collector being slow or absent must never slow down or fail a build.
+func reportMetric(e env, m metric) {
+	path, ok := metricsSocketPath(e)
+	if !ok {
+		return
+	}
+
+	conn, err := net.DialTimeout("unixgram", path, 50*time.Millisecond)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	conn.SetWriteDeadline(time.Now().Add(50 * time.Millisecond))
+	conn.Write(encodeMetric(m))
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsSocketPath(t *testing.T) {
+	if _, ok := metricsSocketPath(newFakeEnv(map[string]string{})); ok {
+		t.Error("expected metrics to be disabled when unset")
+	}
+	path, ok := metricsSocketPath(newFakeEnv(map[string]string{"COMPILER_WRAPPER_METRICS_SOCKET": "/tmp/foo.sock"}))
+	if !ok || path != "/tmp/foo.sock" {
+		t.Errorf("got (%q, %v), want (/tmp/foo.sock, true)", path, ok)
+	}
+}
+
+func TestReportMetricSendsParseableDatagram(t *testing.T) {
+	dir := t.TempDir()
+	sockPath := filepath.Join(dir, "metrics.sock")
+
+	addr := &net.UnixAddr{Name: sockPath, Net: "unixgram"}
+	listener, err := net.ListenUnixgram("unixgram", addr)
+	if err != nil {
+		t.Fatalf("ListenUnixgram: %v", err)
+	}
+	defer listener.Close()
+
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_METRICS_SOCKET": sockPath})
+	reportMetric(e, metric{Duration: 250 * time.Millisecond, ExitCode: 0, CompilerKind: "clang", CacheHit: true})
+
+	listener.SetReadDeadline(time.Now().Add(time.Second))
+	buf := make([]byte, 256)
+	n, err := listener.Read(buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	got := string(buf[:n])
+
+	for _, want := range []string{"duration_ms=250", "exit_code=0", "compiler=clang", "cache_hit=true"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("datagram %q missing %q", got, want)
+		}
+	}
+}
+
+func TestReportMetricNoopWhenUnset(t *testing.T) {
+	// Must not panic or block when no socket is configured.
+	reportMetric(newFakeEnv(map[string]string{}), metric{})
+}
+
+func TestReportMetricIgnoresDialErrors(t *testing.T) {
+	// Pointing at a socket path that doesn't exist must not fail the build.
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_METRICS_SOCKET": "/nonexistent/dir/metrics.sock"})
+	reportMetric(e, metric{})
+}
@@ -0,0 +1,30 @@
+package main
+
+// dedupTargetFlags drops every "-target <value>" pair but the last one in
+// args, for nested clang invocations (such as a resource-dir probe run
+// against a -Xclang-path= sub-compiler) that can end up carrying two: one
+// inherited from the outer command and one the wrapper injects itself.
+// clang itself takes the last -target it sees, but leaving both on the
+// command line is confusing to read and to diff, so the wrapper collapses
+// them down to one before constructing the nested command.
+func dedupTargetFlags(args []string) []string {
+	lastTarget := -1
+	for i, arg := range args {
+		if arg == "-target" && i+1 < len(args) {
+			lastTarget = i
+		}
+	}
+	if lastTarget == -1 {
+		return args
+	}
+
+	newArgs := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "-target" && i+1 < len(args) && i != lastTarget {
+			i++
+			continue
+		}
+		newArgs = append(newArgs, args[i])
+	}
+	return newArgs
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDedupTargetFlagsKeepsLastOfTwo(t *testing.T) {
+	args := []string{"-print-resource-dir", "-target", "arm-linux-gnueabihf", "-target", "x86_64-linux-gnu"}
+
+	got := dedupTargetFlags(args)
+
+	want := []string{"-print-resource-dir", "-target", "x86_64-linux-gnu"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	count := 0
+	for _, a := range got {
+		if a == "-target" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected exactly one -target in the nested probe command, got %d in %v", count, got)
+	}
+}
+
+func TestDedupTargetFlagsNoopWithSingleTarget(t *testing.T) {
+	args := []string{"-print-resource-dir", "-target", "arm-linux-gnueabihf"}
+
+	got := dedupTargetFlags(args)
+
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("got %v, want %v", got, args)
+	}
+}
+
+func TestDedupTargetFlagsNoopWithoutTarget(t *testing.T) {
+	args := []string{"-print-resource-dir"}
+
+	got := dedupTargetFlags(args)
+
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("got %v, want %v", got, args)
+	}
+}
+
+func TestDedupTargetFlagsDescribesNestedProbeCommand(t *testing.T) {
+	// A -Xclang-path= nested probe that inherited the outer -target and
+	// also had one injected by the wrapper itself.
+	args := []string{"-print-resource-dir", "-target", "arm-linux-gnueabihf", "-target", "arm-linux-gnueabihf"}
+
+	got := dedupTargetFlags(args)
+
+	if strings.Count(strings.Join(got, " "), "-target") != 1 {
+		t.Errorf("expected exactly one -target in %v", got)
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+// hasUserFCommonFlag reports whether compilerCmd already asked for the
+// opposite (-fcommon), in which case the user's choice wins.
+func hasUserFCommonFlag(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == "-fcommon" {
+			return true
+		}
+	}
+	return false
+}
+
+// processForceNoCommon injects -fno-common when cfg.ForceNoCommon is set,
+// unless the user already passed -fcommon, so tentative-definition clashes
+// across translation units become multiple-definition link errors instead
+// of silently merging -- easier to catch in CI than to debug after the
+// fact.
+func processForceNoCommon(cfg *config, compilerCmd *command) *command {
+	if !cfg.ForceNoCommon || hasUserFCommonFlag(compilerCmd) {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-fno-common")
+	return &newCmd
+}
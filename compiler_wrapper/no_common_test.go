@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestProcessForceNoCommonInjectsWhenEnabled(t *testing.T) {
+	cfg := &config{ForceNoCommon: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processForceNoCommon(cfg, cmd)
+
+	last := got.Args[len(got.Args)-1]
+	if last != "-fno-common" {
+		t.Errorf("last arg = %q, want -fno-common", last)
+	}
+}
+
+func TestProcessForceNoCommonRespectsUserFCommon(t *testing.T) {
+	cfg := &config{ForceNoCommon: true}
+	cmd := &command{Args: []string{"-c", "foo.c", "-fcommon"}}
+
+	got := processForceNoCommon(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when the user passed -fcommon")
+	}
+}
+
+func TestProcessForceNoCommonNoopByDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processForceNoCommon(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected the command to be returned unmodified when ForceNoCommon is unset")
+	}
+}
@@ -0,0 +1,22 @@
+//go:build cgo
+
+package main
+
+import "testing"
+
+func TestEffectiveExecStrategyBypassesLibcWhenDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{
+		"COMPILER_WRAPPER_EXEC_STRATEGY": "execv",
+		"COMPILER_WRAPPER_NO_LIBC_EXEC":  "1",
+	})
+	if got := effectiveExecStrategy(e); got != execStrategyGo {
+		t.Errorf("effectiveExecStrategy() = %q, want %q", got, execStrategyGo)
+	}
+}
+
+func TestEffectiveExecStrategyDefaultsToSelectedStrategy(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_EXEC_STRATEGY": "execve"})
+	if got := effectiveExecStrategy(e); got != execStrategyExecve {
+		t.Errorf("effectiveExecStrategy() = %q, want %q", got, execStrategyExecve)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+// oldWrapperPythonEnvVar overrides the python interpreter used to invoke
+// the old (python-based) wrapper during comparison, for environments where
+// only a python3-compatible shim is available under that name.
+const oldWrapperPythonEnvVar = "COMPARE_WRAPPER_PYTHON"
+
+// defaultOldWrapperPython is the interpreter the old wrapper has always
+// been invoked with, kept as the default so existing setups that don't set
+// COMPARE_WRAPPER_PYTHON see no change in behavior.
+const defaultOldWrapperPython = "/usr/bin/python2"
+
+// oldWrapperPython resolves which python interpreter to invoke the old
+// wrapper with: COMPARE_WRAPPER_PYTHON if set, otherwise
+// defaultOldWrapperPython.
+func oldWrapperPython(e env) string {
+	if v, ok := e.getenv(oldWrapperPythonEnvVar); ok && v != "" {
+		return v
+	}
+	return defaultOldWrapperPython
+}
+
+// buildOldWrapperCommand constructs the command used to invoke the old
+// wrapper at oldWrapperPath for comparison against compilerCmd, run
+// through oldWrapperPython (see COMPARE_WRAPPER_PYTHON) rather than a
+// hard-coded interpreter.
+func buildOldWrapperCommand(e env, oldWrapperPath string, compilerCmd *command) *command {
+	args := append([]string{oldWrapperPath}, compilerCmd.Args...)
+	return &command{
+		Path: oldWrapperPython(e),
+		Args: args,
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import "testing"
+
+func TestOldWrapperPythonDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := oldWrapperPython(e); got != defaultOldWrapperPython {
+		t.Errorf("got %q, want %q", got, defaultOldWrapperPython)
+	}
+}
+
+func TestOldWrapperPythonHonorsOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{oldWrapperPythonEnvVar: "/usr/bin/python3-shim"})
+	if got := oldWrapperPython(e); got != "/usr/bin/python3-shim" {
+		t.Errorf("got %q, want /usr/bin/python3-shim", got)
+	}
+}
+
+func TestBuildOldWrapperCommandUsesOverridePath(t *testing.T) {
+	e := newFakeEnv(map[string]string{oldWrapperPythonEnvVar: "/usr/bin/python3-shim"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := buildOldWrapperCommand(e, "/usr/bin/old_wrapper", cmd)
+
+	if got.Path != "/usr/bin/python3-shim" {
+		t.Errorf("Path = %q, want /usr/bin/python3-shim", got.Path)
+	}
+	want := []string{"/usr/bin/old_wrapper", "-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestBuildOldWrapperCommandUsesDefaultPython(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := buildOldWrapperCommand(e, "/usr/bin/old_wrapper", cmd)
+
+	if got.Path != defaultOldWrapperPython {
+		t.Errorf("Path = %q, want %q", got.Path, defaultOldWrapperPython)
+	}
+}
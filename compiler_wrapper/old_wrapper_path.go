@@ -0,0 +1,14 @@
+package main
+
+import "fmt"
+
+// maybeWarnOldWrapperPath writes a one-time warning to e's stderr when
+// cfg.OldWrapperPath is set outside the package's own test phase, since
+// leaving it configured in a production build silently doubles every
+// compile by also invoking the old wrapper for comparison.
+func maybeWarnOldWrapperPath(e env, cfg *config) {
+	if cfg.OldWrapperPath == "" || isInTestStage(e) {
+		return
+	}
+	fmt.Fprintf(e.stderr(), "warning: OldWrapperPath is set to %q outside of testing; unset it to avoid doubling every compile\n", cfg.OldWrapperPath)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMaybeWarnOldWrapperPathWarnsInProduction(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{OldWrapperPath: "/usr/bin/old_wrapper"}
+
+	maybeWarnOldWrapperPath(e, cfg)
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, "OldWrapperPath") || !strings.Contains(got, "/usr/bin/old_wrapper") {
+		t.Errorf("expected a warning mentioning OldWrapperPath, got %q", got)
+	}
+}
+
+func TestMaybeWarnOldWrapperPathSilentDuringTestStage(t *testing.T) {
+	e := newFakeEnv(map[string]string{"EBUILD_PHASE": "test"})
+	cfg := &config{OldWrapperPath: "/usr/bin/old_wrapper"}
+
+	maybeWarnOldWrapperPath(e, cfg)
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no warning during the test phase, got %q", e.stderrBuf.String())
+	}
+}
+
+func TestMaybeWarnOldWrapperPathSilentWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{}
+
+	maybeWarnOldWrapperPath(e, cfg)
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no warning when OldWrapperPath is unset, got %q", e.stderrBuf.String())
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import "strings"
+
+// processOptRecordPath normalizes where -fsave-optimization-record writes
+// its .opt.yaml file. Left to the compiler, it lands in the cwd, which gets
+// lost on remote builds; when an artifacts dir is configured (see
+// compilerArtifactsDir), this injects an explicit
+// -foptimization-record-file= pointing there instead, named by a hash of
+// the command's output path so concurrent compiles don't collide. A
+// user-specified -foptimization-record-file is left alone.
+func processOptRecordPath(e env, cfg *config, compilerCmd *command) *command {
+	artifactsDir, _ := compilerArtifactsDir(e, cfg)
+	if artifactsDir == "" {
+		return compilerCmd
+	}
+
+	hasSaveRecord := false
+	var output string
+	for i, arg := range compilerCmd.Args {
+		if arg == "-fsave-optimization-record" {
+			hasSaveRecord = true
+		}
+		if strings.HasPrefix(arg, "-foptimization-record-file=") {
+			return compilerCmd
+		}
+		if arg == "-o" && i+1 < len(compilerCmd.Args) {
+			output = compilerCmd.Args[i+1]
+		}
+	}
+	if !hasSaveRecord || output == "" {
+		return compilerCmd
+	}
+
+	recordFile := artifactsDir + "/" + randomSeedForOutput(output) + ".opt.yaml"
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-foptimization-record-file="+recordFile)
+	return &newCmd
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProcessOptRecordPathInjectsUnderArtifactsDir(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{ArtifactsDir: "/artifacts"}
+	cmd := &command{Args: []string{"-c", "foo.c", "-fsave-optimization-record", "-o", "foo.o"}}
+
+	got := processOptRecordPath(e, cfg, cmd)
+	last := got.Args[len(got.Args)-1]
+	if !strings.HasPrefix(last, "-foptimization-record-file=/artifacts/") || !strings.HasSuffix(last, ".opt.yaml") {
+		t.Errorf("got %q", last)
+	}
+}
+
+func TestProcessOptRecordPathRespectsUserOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{ArtifactsDir: "/artifacts"}
+	cmd := &command{Args: []string{"-c", "foo.c", "-fsave-optimization-record", "-foptimization-record-file=/custom/path.yaml", "-o", "foo.o"}}
+
+	got := processOptRecordPath(e, cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected command to be returned unchanged, got %v", got.Args)
+	}
+}
+
+func TestProcessOptRecordPathNoopWithoutFlag(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{ArtifactsDir: "/artifacts"}
+	cmd := &command{Args: []string{"-c", "foo.c", "-o", "foo.o"}}
+	if got := processOptRecordPath(e, cfg, cmd); got != cmd {
+		t.Errorf("expected no-op without -fsave-optimization-record, got %v", got.Args)
+	}
+}
+
+func TestProcessOptRecordPathNoopWithoutArtifactsDir(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.c", "-fsave-optimization-record", "-o", "foo.o"}}
+	if got := processOptRecordPath(e, cfg, cmd); got != cmd {
+		t.Errorf("expected no-op without ArtifactsDir, got %v", got.Args)
+	}
+}
+
+func TestProcessOptRecordPathEnvOverridesConfig(t *testing.T) {
+	e := newFakeEnv(map[string]string{artifactsDirEnvVar: "/from-env"})
+	cfg := &config{ArtifactsDir: "/from-config"}
+	cmd := &command{Args: []string{"-c", "foo.c", "-fsave-optimization-record", "-o", "foo.o"}}
+
+	got := processOptRecordPath(e, cfg, cmd)
+	last := got.Args[len(got.Args)-1]
+	if !strings.HasPrefix(last, "-foptimization-record-file=/from-env/") {
+		t.Errorf("got %q, want it to use the env override dir", last)
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"syscall"
+)
+
+// serializeOutputsEnvVar opts into taking a flock on the compile's output
+// path before running it, for the first build of a cache, where several
+// concurrent compiles writing the same -o target can thrash each other.
+const serializeOutputsEnvVar = "COMPILER_WRAPPER_SERIALIZE_OUTPUTS"
+
+// serializeOutputsEnabled reports whether COMPILER_WRAPPER_SERIALIZE_OUTPUTS=1
+// is set.
+func serializeOutputsEnabled(e env) bool {
+	v, ok := e.getenv(serializeOutputsEnvVar)
+	return ok && v == "1"
+}
+
+// outputLockPath derives the lockfile path for a given -o target.
+func outputLockPath(outputPath string) string {
+	return outputPath + ".wrapper-lock"
+}
+
+// withOutputLock runs fn while holding an exclusive flock on
+// outputPath(compilerCmd)'s derived lockfile, when serializeOutputsEnabled
+// and compilerCmd has an -o target. With no -o (or the feature disabled),
+// locking is skipped entirely and fn runs immediately.
+func withOutputLock(e env, compilerCmd *command, fn func() error) error {
+	if !serializeOutputsEnabled(e) {
+		return fn()
+	}
+	path, ok := outputPath(compilerCmd)
+	if !ok {
+		return fn()
+	}
+
+	lockPath := outputLockPath(path)
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fn()
+	}
+	defer f.Close()
+
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX); err != nil {
+		return fn()
+	}
+	defer syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+
+	return fn()
+}
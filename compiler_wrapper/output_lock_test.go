@@ -0,0 +1,71 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWithOutputLockCreatesAndReleasesLockfile(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "foo.o")
+	e := newFakeEnv(map[string]string{serializeOutputsEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-o", out}}
+
+	called := false
+	if err := withOutputLock(e, cmd, func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected fn to run")
+	}
+	if _, err := os.Stat(outputLockPath(out)); err != nil {
+		t.Errorf("expected lockfile to exist, got %v", err)
+	}
+
+	// The lock must have been released: a second acquisition must not hang
+	// or fail.
+	called = false
+	if err := withOutputLock(e, cmd, func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error on second acquisition: %v", err)
+	}
+	if !called {
+		t.Error("expected fn to run on second acquisition")
+	}
+}
+
+func TestWithOutputLockSkippedWithoutOutputFlag(t *testing.T) {
+	e := newFakeEnv(map[string]string{serializeOutputsEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	called := false
+	if err := withOutputLock(e, cmd, func() error {
+		called = true
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected fn to run even without an -o target")
+	}
+}
+
+func TestWithOutputLockSkippedWhenDisabled(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "foo.o")
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c", "-o", out}}
+
+	if err := withOutputLock(e, cmd, func() error { return nil }); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(outputLockPath(out)); err == nil {
+		t.Error("expected no lockfile to be created when disabled")
+	}
+}
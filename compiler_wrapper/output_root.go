@@ -0,0 +1,58 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// outputRootEnvVar, when set, requires every compile's -o output to land
+// under that directory, for hermetic builds that want a guarantee nothing
+// escapes the declared output tree.
+const outputRootEnvVar = "COMPILER_WRAPPER_OUTPUT_ROOT"
+
+// outputPath returns the path compilerCmd's -o flag names, handling both
+// the separated ("-o path") and combined ("-opath") forms. With multiple -o
+// flags, the last one wins, matching gcc/clang's own behavior.
+func outputPath(compilerCmd *command) (path string, ok bool) {
+	for i := 0; i < len(compilerCmd.Args); i++ {
+		arg := compilerCmd.Args[i]
+		switch {
+		case arg == "-o":
+			if i+1 < len(compilerCmd.Args) {
+				path, ok = compilerCmd.Args[i+1], true
+				i++
+			}
+		case strings.HasPrefix(arg, "-o") && arg != "-o":
+			path, ok = strings.TrimPrefix(arg, "-o"), true
+		}
+	}
+	return path, ok
+}
+
+// checkOutputRoot returns a userError if COMPILER_WRAPPER_OUTPUT_ROOT is
+// set and compilerCmd's -o output doesn't resolve under it. It's a no-op
+// when the env var is unset or the command has no -o flag at all (e.g. a
+// preprocess-only invocation).
+func checkOutputRoot(e env, compilerCmd *command) error {
+	root, ok := e.getenv(outputRootEnvVar)
+	if !ok || root == "" {
+		return nil
+	}
+	path, ok := outputPath(compilerCmd)
+	if !ok {
+		return nil
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return newUserError("output path %q could not be resolved: %v", path, err)
+	}
+	rel, err := filepath.Rel(absRoot, absPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return newUserError("output path %q is outside the allowed output root %q", path, root)
+	}
+	return nil
+}
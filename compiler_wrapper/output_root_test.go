@@ -0,0 +1,63 @@
+package main
+
+import "testing"
+
+func TestOutputPathSeparatedForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-o", "foo.o", "foo.c"}}
+	path, ok := outputPath(cmd)
+	if !ok || path != "foo.o" {
+		t.Errorf("got (%q, %v), want (foo.o, true)", path, ok)
+	}
+}
+
+func TestOutputPathCombinedForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-ofoo.o", "foo.c"}}
+	path, ok := outputPath(cmd)
+	if !ok || path != "foo.o" {
+		t.Errorf("got (%q, %v), want (foo.o, true)", path, ok)
+	}
+}
+
+func TestOutputPathLastWins(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-o", "first.o", "foo.c", "-o", "second.o"}}
+	path, ok := outputPath(cmd)
+	if !ok || path != "second.o" {
+		t.Errorf("got (%q, %v), want (second.o, true)", path, ok)
+	}
+}
+
+func TestCheckOutputRootInRootPasses(t *testing.T) {
+	e := newFakeEnv(map[string]string{outputRootEnvVar: "/build/out"})
+	cmd := &command{Args: []string{"-c", "-o", "/build/out/obj/foo.o", "foo.c"}}
+	if err := checkOutputRoot(e, cmd); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestCheckOutputRootOutOfRootErrors(t *testing.T) {
+	e := newFakeEnv(map[string]string{outputRootEnvVar: "/build/out"})
+	cmd := &command{Args: []string{"-c", "-o", "/tmp/evil/foo.o", "foo.c"}}
+	err := checkOutputRoot(e, cmd)
+	if err == nil {
+		t.Fatal("expected an error for an out-of-root output")
+	}
+	if !isUserError(err) {
+		t.Errorf("expected a userError, got %T", err)
+	}
+}
+
+func TestCheckOutputRootNoopWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-o", "/tmp/evil/foo.o", "foo.c"}}
+	if err := checkOutputRoot(e, cmd); err != nil {
+		t.Errorf("expected no error when OUTPUT_ROOT is unset, got %v", err)
+	}
+}
+
+func TestCheckOutputRootNoopWithoutOutputFlag(t *testing.T) {
+	e := newFakeEnv(map[string]string{outputRootEnvVar: "/build/out"})
+	cmd := &command{Args: []string{"-E", "foo.c"}}
+	if err := checkOutputRoot(e, cmd); err != nil {
+		t.Errorf("expected no error without -o, got %v", err)
+	}
+}
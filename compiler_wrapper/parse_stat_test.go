@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestParseParentPidFromPidStatCommWithParen(t *testing.T) {
+	ppid, comm, err := parseParentPidFromPidStat("123 (weird)name) S 7 0 0\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comm != "weird)name" {
+		t.Errorf("comm = %q, want %q", comm, "weird)name")
+	}
+	if ppid != 7 {
+		t.Errorf("ppid = %d, want 7", ppid)
+	}
+}
+
+func TestParseParentPidFromPidStatCommWithNewline(t *testing.T) {
+	ppid, comm, err := parseParentPidFromPidStat("123 (weird\nname) S 7 0 0\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comm != "weird\nname" {
+		t.Errorf("comm = %q, want %q", comm, "weird\nname")
+	}
+	if ppid != 7 {
+		t.Errorf("ppid = %d, want 7", ppid)
+	}
+}
+
+func TestParseParentPidFromPidStatFallsBackOnCorruptedStateField(t *testing.T) {
+	// The state field here ("55") isn't a valid single-letter state, so the
+	// normal fields[1]-is-ppid assumption can't be trusted; the fallback
+	// should scan forward and treat the second integer-looking field (7) as
+	// the ppid.
+	ppid, comm, err := parseParentPidFromPidStat("123 (comm) 55 7 0 0\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if comm != "comm" {
+		t.Errorf("comm = %q, want %q", comm, "comm")
+	}
+	if ppid != 7 {
+		t.Errorf("ppid = %d, want 7", ppid)
+	}
+}
+
+func TestParseParentPidFromPidStatMalformedReturnsError(t *testing.T) {
+	if _, _, err := parseParentPidFromPidStat("no parens here"); err == nil {
+		t.Error("expected an error for a line with no comm parens")
+	}
+	if _, _, err := parseParentPidFromPidStat("123 (comm) S"); err == nil {
+		t.Error("expected an error when no integer-looking fields follow comm")
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+// noPieMarkerFlag is a synthetic flag (not understood by any real
+// compiler) that callers can pass to mark an invocation as needing to stay
+// non-PIE, e.g. early-boot objects. It's always stripped from the final
+// command, similar to how the werror marker works.
+const noPieMarkerFlag = "-D_CROSTC_NO_PIE"
+
+// userPieOrPicFlags are flags the user can pass to take explicit control of
+// position-independent-code/executable behavior. Any of them present means
+// the wrapper shouldn't inject its own -fPIE -pie on top, since -fPIC in
+// particular must win over an injected -fPIE.
+var userPieOrPicFlags = map[string]bool{
+	"-fPIE": true, "-fpie": true, "-pie": true,
+	"-fPIC": true, "-fpic": true,
+	"-no-pie": true, "-fno-pie": true, "-fno-PIE": true,
+}
+
+// processPieFlags injects -fPIE -pie for non-eabi targets, unless the
+// invocation carries noPieMarkerFlag (which suppresses the injection for
+// objects, like early boot code, that must stay non-PIE) or the user
+// already specified a PIE/PIC-family flag of their own. The marker is
+// stripped from the resulting command either way.
+func processPieFlags(isEabiTarget bool, compilerCmd *command) *command {
+	noPIE := false
+	userSpecified := false
+	newArgs := make([]string, 0, len(compilerCmd.Args)+2)
+	for _, arg := range compilerCmd.Args {
+		if arg == noPieMarkerFlag {
+			noPIE = true
+			continue
+		}
+		if userPieOrPicFlags[arg] {
+			userSpecified = true
+		}
+		newArgs = append(newArgs, arg)
+	}
+
+	newCmd := *compilerCmd
+	if !isEabiTarget && !noPIE && !userSpecified {
+		newArgs = append(newArgs, "-fPIE", "-pie")
+	}
+	newCmd.Args = newArgs
+	return &newCmd
+}
@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+func TestProcessPieFlagsInjectsForNonEabi(t *testing.T) {
+	got := processPieFlags(false, &command{Args: []string{"-c", "foo.c"}})
+	want := []string{"-c", "foo.c", "-fPIE", "-pie"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("Args = %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("Args = %v, want %v", got.Args, want)
+		}
+	}
+}
+
+func TestProcessPieFlagsSkipsEabi(t *testing.T) {
+	got := processPieFlags(true, &command{Args: []string{"-c", "foo.c"}})
+	for _, a := range got.Args {
+		if a == "-fPIE" || a == "-pie" {
+			t.Errorf("expected no PIE flags for an eabi target, got %v", got.Args)
+		}
+	}
+}
+
+func TestProcessPieFlagsUserFPICSuppressesInjectedFPIE(t *testing.T) {
+	got := processPieFlags(false, &command{Args: []string{"-c", "foo.c", "-fPIC"}})
+	for _, a := range got.Args {
+		if a == "-fPIE" || a == "-pie" {
+			t.Errorf("expected a user -fPIC to suppress injected PIE flags, got %v", got.Args)
+		}
+	}
+}
+
+func TestProcessPieFlagsUserFpieSuppressesInjection(t *testing.T) {
+	got := processPieFlags(false, &command{Args: []string{"-c", "foo.c", "-fpie"}})
+	count := 0
+	for _, a := range got.Args {
+		if a == "-fPIE" || a == "-pie" || a == "-fpie" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected only the user's own -fpie to remain, got %v", got.Args)
+	}
+}
+
+func TestProcessPieFlagsNoPieMarkerSuppressesAndIsRemoved(t *testing.T) {
+	got := processPieFlags(false, &command{Args: []string{"-c", "foo.c", noPieMarkerFlag}})
+
+	for _, a := range got.Args {
+		if a == noPieMarkerFlag {
+			t.Errorf("expected the marker to be removed, got %v", got.Args)
+		}
+		if a == "-fPIE" || a == "-pie" {
+			t.Errorf("expected no PIE flags when the marker is present, got %v", got.Args)
+		}
+	}
+}
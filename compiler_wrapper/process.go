@@ -0,0 +1,134 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// parentProcessInfo describes one process in the ancestry chain collected
+// for diagnostic reports.
+type parentProcessInfo struct {
+	PID     int
+	Comm    string
+	Cmdline string
+}
+
+// procRoot is the mount point collectAllParentProcesses reads from; tests
+// point it at a synthetic directory tree instead of the real /proc.
+var procRoot = "/proc"
+
+// defaultParentProcessMaxDepth bounds how many ancestors
+// collectAllParentProcesses walks by default, so deep containerized PID
+// namespaces don't slow report generation.
+const defaultParentProcessMaxDepth = 20
+
+// parentProcessMaxDepth reads the walk-depth cap from
+// CROSTC_REPORT_MAX_DEPTH, falling back to defaultParentProcessMaxDepth
+// when it's unset or not a positive integer.
+func parentProcessMaxDepth(e env) int {
+	v, ok := e.getenv("CROSTC_REPORT_MAX_DEPTH")
+	if !ok {
+		return defaultParentProcessMaxDepth
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return defaultParentProcessMaxDepth
+	}
+	return n
+}
+
+// collectAllParentProcesses walks the parent-process chain starting at pid,
+// reading <procRoot>/<pid>/stat and <procRoot>/<pid>/cmdline for each
+// ancestor up to (but not including) pid 1, or until it has walked
+// parentProcessMaxDepth(e) ancestors, whichever comes first; truncated
+// reports whether the cap was hit. If procRoot isn't available at all
+// (non-Linux, or a restricted container), it returns an empty slice and a
+// nil error rather than a confusing partial result. If procRoot exists but
+// collection fails partway through, the chain collected so far is returned
+// alongside the error.
+func collectAllParentProcesses(e env, pid int) (chain []parentProcessInfo, truncated bool, err error) {
+	if _, err := os.Stat(procRoot); err != nil {
+		return nil, false, nil
+	}
+
+	maxDepth := parentProcessMaxDepth(e)
+	for pid > 1 {
+		if len(chain) >= maxDepth {
+			return chain, true, nil
+		}
+		stat, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "stat"))
+		if err != nil {
+			return chain, false, fmt.Errorf("reading stat for pid %d: %w", pid, err)
+		}
+		ppid, comm, err := parseParentPidFromPidStat(string(stat))
+		if err != nil {
+			return chain, false, fmt.Errorf("parsing stat for pid %d: %w", pid, err)
+		}
+		cmdline, _ := readCmdline(pid)
+		chain = append(chain, parentProcessInfo{PID: pid, Comm: comm, Cmdline: cmdline})
+		pid = ppid
+	}
+	return chain, false, nil
+}
+
+// readCmdline reads <procRoot>/<pid>/cmdline, a NUL-separated argv, and
+// joins it with spaces for display.
+func readCmdline(pid int) (string, error) {
+	raw, err := os.ReadFile(filepath.Join(procRoot, strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return "", err
+	}
+	parts := strings.Split(strings.TrimRight(string(raw), "\x00"), "\x00")
+	return strings.Join(parts, " "), nil
+}
+
+// parseParentPidFromPidStat extracts the parent pid and comm (process name)
+// from the contents of /proc/<pid>/stat. The comm field is parenthesized
+// and may itself contain spaces, newlines, or even ")", so it's located by
+// the first "(" and the matching last ")" before splitting the remaining
+// whitespace-separated fields (state, ppid, ...).
+//
+// As a sanity check, the field right after comm is expected to be the
+// single-character state; if it isn't, the normal field alignment can't be
+// trusted (e.g. a renamed comm confused the boundary), so this falls back
+// to scanning forward for the first two integer-looking fields and trusts
+// the second one is the ppid, mirroring the normal "state, ppid" shape.
+func parseParentPidFromPidStat(stat string) (ppid int, comm string, err error) {
+	open := strings.IndexByte(stat, '(')
+	closeIdx := strings.LastIndexByte(stat, ')')
+	if open < 0 || closeIdx < 0 || closeIdx < open {
+		return 0, "", fmt.Errorf("malformed /proc stat line: %q", stat)
+	}
+	comm = stat[open+1 : closeIdx]
+
+	fields := strings.Fields(stat[closeIdx+1:])
+	if len(fields) >= 2 && isSingleLetterState(fields[0]) {
+		if ppid, err := strconv.Atoi(fields[1]); err == nil {
+			return ppid, comm, nil
+		}
+	}
+
+	var ints []int
+	for _, f := range fields {
+		if n, convErr := strconv.Atoi(f); convErr == nil {
+			ints = append(ints, n)
+			if len(ints) == 2 {
+				return ints[1], comm, nil
+			}
+		}
+	}
+	return 0, "", fmt.Errorf("malformed /proc stat line: %q", stat)
+}
+
+// isSingleLetterState reports whether f looks like a /proc stat state
+// field: a single alphabetic character (R, S, D, Z, T, ...).
+func isSingleLetterState(f string) bool {
+	if len(f) != 1 {
+		return false
+	}
+	c := f[0]
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z'
+}
@@ -0,0 +1,12 @@
+package main
+
+import "os/exec"
+
+// setNewProcessGroup configures cmd to start in its own new process group
+// (pgid == its own pid), so a timeout or signal handler can kill the whole
+// group -- including any sub-processes the compiler itself spawns, like
+// clang's integrated cc1 helper -- with a single killpg, instead of leaking
+// orphans behind a dead wrapper.
+func setNewProcessGroup(cmd *exec.Cmd) {
+	setSysProcAttrNewProcessGroup(cmd)
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setSysProcAttrNewProcessGroup sets Setpgid so cmd starts its own process
+// group, letting callers kill the whole group together.
+func setSysProcAttrNewProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
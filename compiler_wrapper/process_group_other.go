@@ -0,0 +1,10 @@
+//go:build !linux
+
+package main
+
+import "os/exec"
+
+// setSysProcAttrNewProcessGroup is a no-op outside Linux, where this
+// wrapper isn't deployed; process-group killing isn't available there via
+// the same mechanism.
+func setSysProcAttrNewProcessGroup(cmd *exec.Cmd) {}
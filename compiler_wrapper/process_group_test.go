@@ -0,0 +1,28 @@
+//go:build linux
+
+package main
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+)
+
+func TestSetNewProcessGroupStartsChildInNewGroup(t *testing.T) {
+	cmd := exec.Command("/bin/sh", "-c", "sleep 1")
+	setNewProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer cmd.Process.Kill()
+	defer cmd.Wait()
+
+	pgid, err := syscall.Getpgid(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("getpgid: %v", err)
+	}
+	if pgid != cmd.Process.Pid {
+		t.Errorf("expected the child to be its own process group leader (pgid %d), got pgid %d", cmd.Process.Pid, pgid)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func withProcRoot(t *testing.T, root string) {
+	t.Helper()
+	old := procRoot
+	procRoot = root
+	t.Cleanup(func() { procRoot = old })
+}
+
+func TestCollectAllParentProcessesNoProcRoot(t *testing.T) {
+	withProcRoot(t, filepath.Join(t.TempDir(), "does-not-exist"))
+
+	chain, _, err := collectAllParentProcesses(newFakeEnv(map[string]string{}), 1234)
+	if err != nil {
+		t.Fatalf("expected no error when procRoot is absent, got %v", err)
+	}
+	if len(chain) != 0 {
+		t.Errorf("expected an empty chain, got %v", chain)
+	}
+}
+
+func TestCollectAllParentProcessesWalksChain(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProc(t, root, 3, 2, "leaf", "leaf\x00arg\x00")
+	writeFakeProc(t, root, 2, 1, "mid", "mid\x00")
+	withProcRoot(t, root)
+
+	chain, truncated, err := collectAllParentProcesses(newFakeEnv(map[string]string{}), 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if truncated {
+		t.Error("expected no truncation")
+	}
+	if len(chain) != 2 {
+		t.Fatalf("expected a chain of 2, got %v", chain)
+	}
+	if chain[0].PID != 3 || chain[0].Comm != "leaf" {
+		t.Errorf("chain[0] = %+v", chain[0])
+	}
+	if chain[1].PID != 2 || chain[1].Comm != "mid" {
+		t.Errorf("chain[1] = %+v", chain[1])
+	}
+}
+
+func TestCollectAllParentProcessesPartialFailureReturnsChainSoFar(t *testing.T) {
+	root := t.TempDir()
+	// pid 3's stat points at pid 2, but pid 2's stat is missing.
+	writeFakeProc(t, root, 3, 2, "leaf", "leaf\x00")
+	withProcRoot(t, root)
+
+	chain, _, err := collectAllParentProcesses(newFakeEnv(map[string]string{}), 3)
+	if err == nil {
+		t.Fatal("expected an error for the missing ancestor")
+	}
+	if len(chain) != 1 || chain[0].PID != 3 {
+		t.Errorf("expected the partial chain to still contain pid 3, got %v", chain)
+	}
+}
+
+func writeFakeProc(t *testing.T, root string, pid, ppid int, comm, cmdline string) {
+	t.Helper()
+	dir := filepath.Join(root, strconv.Itoa(pid))
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	stat := strconv.Itoa(pid) + " (" + comm + ") S " + strconv.Itoa(ppid) + " 0 0\n"
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPopulateParentProcessesNoProcRootLeavesErrorEmpty(t *testing.T) {
+	withProcRoot(t, filepath.Join(t.TempDir(), "nope"))
+
+	r := &report{}
+	chain := populateParentProcesses(r, newFakeEnv(map[string]string{}), 1234)
+
+	if len(chain) != 0 {
+		t.Errorf("expected an empty chain, got %v", chain)
+	}
+	if r.ParentProcessError != "" {
+		t.Errorf("expected ParentProcessError to stay empty, got %q", r.ParentProcessError)
+	}
+}
+
+func TestCollectAllParentProcessesTruncatesAtMaxDepth(t *testing.T) {
+	root := t.TempDir()
+	// A chain of 5 processes: 6 -> 5 -> 4 -> 3 -> 2 -> 1.
+	for pid := 6; pid >= 2; pid-- {
+		writeFakeProc(t, root, pid, pid-1, "p", "p\x00")
+	}
+	withProcRoot(t, root)
+
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_MAX_DEPTH": "3"})
+	chain, truncated, err := collectAllParentProcesses(e, 6)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !truncated {
+		t.Error("expected truncation to be reported")
+	}
+	if len(chain) != 3 {
+		t.Errorf("expected exactly 3 ancestors, got %d: %v", len(chain), chain)
+	}
+}
+
+func TestPopulateParentProcessesSetsErrorOnPartialFailure(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProc(t, root, 3, 2, "leaf", "")
+	withProcRoot(t, root)
+
+	r := &report{}
+	populateParentProcesses(r, newFakeEnv(map[string]string{}), 3)
+
+	if r.ParentProcessError == "" {
+		t.Error("expected ParentProcessError to be set for the missing ancestor")
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import "strings"
+
+// promoteWerrorEnvVar names a comma-separated list of warnings to promote
+// to fatal fleet-wide, the inverse of force-disable-werror: some teams want
+// specific warnings to always break the build even where -Werror itself
+// isn't on.
+const promoteWerrorEnvVar = "COMPILER_WRAPPER_PROMOTE_WERROR"
+
+// promotedWerrorNames parses COMPILER_WRAPPER_PROMOTE_WERROR into the list
+// of warning names to promote, trimming whitespace and dropping empty
+// entries. It returns nil if the var is unset or empty.
+func promotedWerrorNames(e env) []string {
+	v, ok := e.getenv(promoteWerrorEnvVar)
+	if !ok || v == "" {
+		return nil
+	}
+	var names []string
+	for _, entry := range strings.Split(v, ",") {
+		if name := strings.TrimSpace(entry); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// processPromoteWerror appends -Werror=<name> for every warning named in
+// COMPILER_WRAPPER_PROMOTE_WERROR, after the user's own args so a
+// promoted warning always wins over anything the user passed to relax it.
+func processPromoteWerror(e env, compilerCmd *command) *command {
+	names := promotedWerrorNames(e)
+	if len(names) == 0 {
+		return compilerCmd
+	}
+	flags := make([]string, len(names))
+	for i, name := range names {
+		flags[i] = "-Werror=" + name
+	}
+	return addPostUserArgs(compilerCmd, flags)
+}
@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestProcessPromoteWerrorInjectsEachWarning(t *testing.T) {
+	e := newFakeEnv(map[string]string{promoteWerrorEnvVar: "unused-variable, format"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processPromoteWerror(e, cmd)
+
+	want := []string{"-c", "foo.c", "-Werror=unused-variable", "-Werror=format"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessPromoteWerrorEmptySafe(t *testing.T) {
+	e := newFakeEnv(map[string]string{promoteWerrorEnvVar: ""})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processPromoteWerror(e, cmd)
+
+	if got != cmd {
+		t.Error("expected no change for an empty COMPILER_WRAPPER_PROMOTE_WERROR")
+	}
+}
+
+func TestProcessPromoteWerrorNoopWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processPromoteWerror(e, cmd)
+
+	if got != cmd {
+		t.Error("expected no change when COMPILER_WRAPPER_PROMOTE_WERROR is unset")
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import "strings"
+
+// queryFlags lists compiler arguments that ask for information about the
+// compiler itself rather than asking it to compile anything. Invocations
+// containing one of these bypass the wrapper's heavy pipeline (ccache,
+// goma, sanitizer flag rewriting, double-build, ...) and exec the real
+// compiler directly, since none of that applies to a metadata query.
+var queryFlags = map[string]bool{
+	"--version":               true,
+	"-v":                      true,
+	"-print-search-dirs":      true,
+	"-print-libgcc-file-name": true,
+	"-print-multi-lib":        true,
+	"-dumpversion":            true,
+	"-dumpmachine":            true,
+}
+
+// isCompilerQueryCommand reports whether compilerCmd only asks the compiler
+// for information about itself, rather than compiling anything.
+func isCompilerQueryCommand(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if queryFlags[arg] {
+			return true
+		}
+		if strings.HasPrefix(arg, "-print-prog-name=") {
+			return true
+		}
+	}
+	return isGenericPrintOrDumpQuery(compilerCmd)
+}
+
+// sourceFileExtensions are suffixes that mark an argument as a source file
+// rather than a flag or flag value, for distinguishing a metadata query
+// from a real compile that happens to also pass a -dump* optimization flag.
+var sourceFileExtensions = []string{
+	".c", ".cc", ".cpp", ".cxx", ".C", ".m", ".mm", ".S", ".s", ".i", ".ii",
+}
+
+// hasSourceFileArg reports whether any argument looks like a source file.
+func hasSourceFileArg(args []string) bool {
+	for _, arg := range args {
+		for _, ext := range sourceFileExtensions {
+			if strings.HasSuffix(arg, ext) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGenericPrintOrDumpQuery catches -print-*/-dump* flags not in the fixed
+// queryFlags set -- queryFlags can't keep up with every new
+// metadata-reporting flag a future compiler release adds -- while being
+// careful not to match -fdump-* flags (like -fdump-tree-all), which are
+// real optimization/diagnostic flags used during an ordinary compile, not
+// standalone queries.
+func isGenericPrintOrDumpQuery(compilerCmd *command) bool {
+	matched := false
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-f") {
+			continue
+		}
+		if strings.HasPrefix(arg, "-print") || strings.HasPrefix(arg, "-dump") {
+			matched = true
+		}
+	}
+	return matched && !hasSourceFileArg(compilerCmd.Args)
+}
@@ -0,0 +1,30 @@
+package main
+
+import "testing"
+
+func TestIsCompilerQueryCommand(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"libgcc file name", []string{"-print-libgcc-file-name"}, true},
+		{"multi lib", []string{"-print-multi-lib"}, true},
+		{"dumpversion", []string{"-dumpversion"}, true},
+		{"dumpmachine", []string{"-dumpmachine"}, true},
+		{"version", []string{"--version"}, true},
+		{"prog name prefix", []string{"-print-prog-name=ld"}, true},
+		{"normal compile", []string{"-c", "foo.c", "-o", "foo.o"}, false},
+		{"novel print flag", []string{"-print-xyz"}, true},
+		{"novel dump flag", []string{"-dump-something"}, true},
+		{"fdump optimization flag with source", []string{"-fdump-tree-all", "-c", "foo.c"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := isCompilerQueryCommand(&command{Args: tc.args})
+			if got != tc.want {
+				t.Errorf("isCompilerQueryCommand(%v) = %v, want %v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
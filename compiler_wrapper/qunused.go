@@ -0,0 +1,18 @@
+package main
+
+// injectQunusedArguments appends -Qunused-arguments for clang invocations so
+// unrecognized flags (often meant for a different compiler) don't turn into
+// hard errors. Setting COMPILER_WRAPPER_NO_QUNUSED=1 opts out of the
+// injection for teams that want unused-flag bugs to surface; a
+// user-supplied -Qunused-arguments is never touched either way.
+func injectQunusedArguments(e env, compilerIsClang bool, compilerCmd *command) *command {
+	if !compilerIsClang {
+		return compilerCmd
+	}
+	if _, noQunused := e.getenv("COMPILER_WRAPPER_NO_QUNUSED"); noQunused {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-Qunused-arguments")
+	return &newCmd
+}
@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestInjectQunusedArgumentsDefaultPresence(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := injectQunusedArguments(e, true, cmd)
+
+	if got.Args[len(got.Args)-1] != "-Qunused-arguments" {
+		t.Errorf("expected -Qunused-arguments to be injected, got %v", got.Args)
+	}
+}
+
+func TestInjectQunusedArgumentsOptOut(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_NO_QUNUSED": "1"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := injectQunusedArguments(e, true, cmd)
+
+	for _, arg := range got.Args {
+		if arg == "-Qunused-arguments" {
+			t.Errorf("expected -Qunused-arguments to not be injected, got %v", got.Args)
+		}
+	}
+}
+
+func TestInjectQunusedArgumentsKeepsUserSupplied(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_NO_QUNUSED": "1"})
+	cmd := &command{Args: []string{"-c", "foo.c", "-Qunused-arguments"}}
+
+	got := injectQunusedArguments(e, true, cmd)
+
+	count := 0
+	for _, arg := range got.Args {
+		if arg == "-Qunused-arguments" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("expected the user-supplied -Qunused-arguments to survive exactly once, got %d occurrences in %v", count, got.Args)
+	}
+}
+
+func TestInjectQunusedArgumentsSkippedForGcc(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := injectQunusedArguments(e, false, cmd)
+
+	if got != cmd {
+		t.Error("expected gcc invocations to be returned unmodified")
+	}
+}
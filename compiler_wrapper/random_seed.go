@@ -0,0 +1,41 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// processRandomSeed injects a deterministic -frandom-seed=<hash> derived
+// from the command's -o output path, when cfg.Reproducible is set and the
+// user hasn't already specified a seed. Anonymous-namespace symbol names
+// depend on -frandom-seed, so without a stable seed identical sources can
+// produce different symbol names across otherwise-identical builds.
+func processRandomSeed(cfg *config, compilerCmd *command) *command {
+	if !cfg.Reproducible {
+		return compilerCmd
+	}
+
+	var output string
+	for i, arg := range compilerCmd.Args {
+		if arg == "-frandom-seed" || strings.HasPrefix(arg, "-frandom-seed=") {
+			return compilerCmd
+		}
+		if arg == "-o" && i+1 < len(compilerCmd.Args) {
+			output = compilerCmd.Args[i+1]
+		}
+	}
+	if output == "" {
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-frandom-seed="+randomSeedForOutput(output))
+	return &newCmd
+}
+
+// randomSeedForOutput derives a stable hex seed from an output path.
+func randomSeedForOutput(output string) string {
+	sum := sha256.Sum256([]byte(output))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,60 @@
+package main
+
+import "testing"
+
+func TestProcessRandomSeedInjectsStableSeed(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.cc", "-o", "foo.o"}}
+
+	got := processRandomSeed(cfg, cmd)
+	want := processRandomSeed(cfg, &command{Args: []string{"-c", "foo.cc", "-o", "foo.o"}})
+
+	if len(got.Args) != len(cmd.Args)+1 {
+		t.Fatalf("expected one flag appended, got %v", got.Args)
+	}
+	seed := got.Args[len(got.Args)-1]
+	wantSeed := want.Args[len(want.Args)-1]
+	if seed != wantSeed {
+		t.Errorf("seed for the same -o should be stable, got %q and %q", seed, wantSeed)
+	}
+}
+
+func TestProcessRandomSeedDiffersForDifferentOutputs(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	a := processRandomSeed(cfg, &command{Args: []string{"-c", "foo.cc", "-o", "foo.o"}})
+	b := processRandomSeed(cfg, &command{Args: []string{"-c", "foo.cc", "-o", "bar.o"}})
+
+	if a.Args[len(a.Args)-1] == b.Args[len(b.Args)-1] {
+		t.Errorf("expected different seeds for different outputs, got the same: %q", a.Args[len(a.Args)-1])
+	}
+}
+
+func TestProcessRandomSeedLeavesExplicitSeedAlone(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.cc", "-frandom-seed=1234", "-o", "foo.o"}}
+
+	got := processRandomSeed(cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected command to be returned unchanged when -frandom-seed is already set, got %v", got.Args)
+	}
+}
+
+func TestProcessRandomSeedNoOutputIsNoop(t *testing.T) {
+	cfg := &config{Reproducible: true}
+	cmd := &command{Args: []string{"-c", "foo.cc"}}
+
+	got := processRandomSeed(cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected command to be returned unchanged without -o, got %v", got.Args)
+	}
+}
+
+func TestProcessRandomSeedDisabledByDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-c", "foo.cc", "-o", "foo.o"}}
+
+	got := processRandomSeed(cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected command to be returned unchanged when Reproducible is false, got %v", got.Args)
+	}
+}
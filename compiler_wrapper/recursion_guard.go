@@ -0,0 +1,50 @@
+package main
+
+import "strconv"
+
+// recursionDepthEnvVar carries how many times the wrapper has re-invoked
+// itself, incremented on each pass and propagated to the child via
+// EnvUpdates. A leading underscore marks it as wrapper-internal state, not
+// something a user is meant to set.
+const recursionDepthEnvVar = "_COMPILER_WRAPPER_DEPTH"
+
+// maxRecursionDepth bounds how many times the wrapper will re-invoke
+// itself before assuming its ".real" compiler is misconfigured to point
+// back at the wrapper, rather than recursing until resource exhaustion.
+const maxRecursionDepth = 8
+
+// checkRecursionDepth reads the current depth from e, and returns the
+// incremented depth plus a userError if incrementing it would exceed
+// maxRecursionDepth. Callers should set the returned depth into the child
+// command's EnvUpdates so the next invocation can detect a repeat.
+func checkRecursionDepth(e env) (depth int, err error) {
+	depth = 0
+	if v, ok := e.getenv(recursionDepthEnvVar); ok {
+		if n, convErr := strconv.Atoi(v); convErr == nil {
+			depth = n
+		}
+	}
+	depth++
+	if depth > maxRecursionDepth {
+		return depth, newUserError("compiler_wrapper invoked itself %d times in a row; check that the configured real compiler doesn't point back at the wrapper", depth)
+	}
+	return depth, nil
+}
+
+// guardAgainstRecursion checks compilerCmd's invocation depth and, if
+// still within bounds, returns a copy of compilerCmd with
+// recursionDepthEnvVar set in EnvUpdates for the next invocation to see.
+func guardAgainstRecursion(e env, compilerCmd *command) (*command, error) {
+	depth, err := checkRecursionDepth(e)
+	if err != nil {
+		return compilerCmd, err
+	}
+
+	newCmd := *compilerCmd
+	newCmd.EnvUpdates = make(map[string]string, len(compilerCmd.EnvUpdates)+1)
+	for k, v := range compilerCmd.EnvUpdates {
+		newCmd.EnvUpdates[k] = v
+	}
+	newCmd.EnvUpdates[recursionDepthEnvVar] = strconv.Itoa(depth)
+	return &newCmd, nil
+}
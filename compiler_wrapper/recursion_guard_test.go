@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestCheckRecursionDepthIncrements(t *testing.T) {
+	e := newFakeEnv(map[string]string{"_COMPILER_WRAPPER_DEPTH": "3"})
+	depth, err := checkRecursionDepth(e)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if depth != 4 {
+		t.Errorf("got %d, want 4", depth)
+	}
+}
+
+func TestCheckRecursionDepthStartsAtOne(t *testing.T) {
+	depth, err := checkRecursionDepth(newFakeEnv(map[string]string{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if depth != 1 {
+		t.Errorf("got %d, want 1", depth)
+	}
+}
+
+func TestCheckRecursionDepthErrorsPastThreshold(t *testing.T) {
+	e := newFakeEnv(map[string]string{"_COMPILER_WRAPPER_DEPTH": "8"})
+	_, err := checkRecursionDepth(e)
+	if !isUserError(err) {
+		t.Fatalf("expected a userError past the threshold, got %v", err)
+	}
+}
+
+func TestGuardAgainstRecursionSetsEnvUpdate(t *testing.T) {
+	e := newFakeEnv(map[string]string{"_COMPILER_WRAPPER_DEPTH": "1"})
+	cmd := &command{EnvUpdates: map[string]string{"OTHER": "1"}}
+
+	got, err := guardAgainstRecursion(e, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.EnvUpdates["_COMPILER_WRAPPER_DEPTH"] != "2" {
+		t.Errorf("got %v", got.EnvUpdates)
+	}
+	if got.EnvUpdates["OTHER"] != "1" {
+		t.Errorf("expected existing EnvUpdates to be preserved, got %v", got.EnvUpdates)
+	}
+}
+
+func TestGuardAgainstRecursionErrorsPastThreshold(t *testing.T) {
+	e := newFakeEnv(map[string]string{"_COMPILER_WRAPPER_DEPTH": "9"})
+	_, err := guardAgainstRecursion(e, &command{})
+	if !isUserError(err) {
+		t.Fatalf("expected a userError, got %v", err)
+	}
+}
+
+func TestGuardAgainstRecursionSimulatedIncreasingDepth(t *testing.T) {
+	var err error
+	for depth := 0; depth <= maxRecursionDepth+1; depth++ {
+		e := newFakeEnv(map[string]string{"_COMPILER_WRAPPER_DEPTH": strconv.Itoa(depth)})
+		_, err = guardAgainstRecursion(e, &command{})
+		if depth < maxRecursionDepth && err != nil {
+			t.Fatalf("unexpected error at depth %d: %v", depth, err)
+		}
+	}
+	if err == nil {
+		t.Fatal("expected recursion past the threshold to eventually error")
+	}
+}
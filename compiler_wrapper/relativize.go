@@ -0,0 +1,20 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// rootRelative rewrites an absolute path under root to a path relative to
+// root. It returns ok=false if path is not absolute or does not live under
+// root.
+func rootRelative(root, path string) (rel string, ok bool) {
+	if !filepath.IsAbs(path) {
+		return "", false
+	}
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return rel, true
+}
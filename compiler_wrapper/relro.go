@@ -0,0 +1,22 @@
+package main
+
+import "strings"
+
+// processInjectFullRelro adds -Wl,-z,now -Wl,-z,relro to link commands for
+// configs that opt in via config.InjectFullRelro, hardening every resulting
+// binary against GOT/PLT overwrite attacks. It does nothing for non-link
+// invocations, or if the user already passed their own -z,now/-z,relro.
+func processInjectFullRelro(cfg *config, compilerCmd *command) *command {
+	if !cfg.InjectFullRelro || !isLinkOnly(compilerCmd) {
+		return compilerCmd
+	}
+	for _, arg := range compilerCmd.Args {
+		if strings.Contains(arg, "-z,now") || strings.Contains(arg, "-z,relro") {
+			return compilerCmd
+		}
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-Wl,-z,now", "-Wl,-z,relro")
+	return &newCmd
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+func TestProcessInjectFullRelroInjectsOnLinkCommand(t *testing.T) {
+	cfg := &config{InjectFullRelro: true}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o"}}
+
+	got := processInjectFullRelro(cfg, cmd)
+
+	want := []string{"-o", "a.out", "foo.o", "-Wl,-z,now", "-Wl,-z,relro"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestProcessInjectFullRelroSuppressedByUserFlag(t *testing.T) {
+	cfg := &config{InjectFullRelro: true}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o", "-Wl,-z,relro"}}
+
+	got := processInjectFullRelro(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected a user-supplied -z,relro to suppress injection")
+	}
+}
+
+func TestProcessInjectFullRelroSkippedForCompileStep(t *testing.T) {
+	cfg := &config{InjectFullRelro: true}
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processInjectFullRelro(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected a compile-only command to be left untouched")
+	}
+}
+
+func TestProcessInjectFullRelroOffByDefault(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{Args: []string{"-o", "a.out", "foo.o"}}
+
+	got := processInjectFullRelro(cfg, cmd)
+
+	if got != cmd {
+		t.Error("expected no injection when InjectFullRelro is unset")
+	}
+}
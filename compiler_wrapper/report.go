@@ -0,0 +1,26 @@
+package main
+
+// report captures diagnostic information the wrapper can attach to a
+// single compile, beyond the plain stderr it forwards.
+type report struct {
+	// ParentProcessError records why parent-process collection failed
+	// partway through, if it did. It's left empty both when collection
+	// succeeded and when /proc was simply unavailable (not an error).
+	ParentProcessError string
+
+	// ParentProcessTruncated records whether the parent-process walk hit
+	// its depth cap before reaching pid 1.
+	ParentProcessTruncated bool
+}
+
+// populateParentProcesses fills r.ParentProcessError (if collection failed
+// partway through) and r.ParentProcessTruncated (if the depth cap was hit),
+// and returns whatever chain of parentProcessInfo was collected for pid.
+func populateParentProcesses(r *report, e env, pid int) []parentProcessInfo {
+	chain, truncated, err := collectAllParentProcesses(e, pid)
+	if err != nil {
+		r.ParentProcessError = err.Error()
+	}
+	r.ParentProcessTruncated = truncated
+	return chain
+}
@@ -0,0 +1,39 @@
+package main
+
+import "strconv"
+
+// defaultReportMaxBytes caps how much captured compiler output a report
+// embeds, so a pathological build that floods stdout/stderr doesn't bloat a
+// report to hundreds of MB.
+const defaultReportMaxBytes = 1 << 20 // 1MiB
+
+// reportTruncatedMarker is appended after truncation so a reader can tell
+// the output was cut rather than legitimately ending there.
+const reportTruncatedMarker = "\n...[truncated]...\n"
+
+// reportMaxBytes returns the configured cap, reading CROSTC_REPORT_MAX_BYTES
+// if set to a positive integer, falling back to defaultReportMaxBytes
+// otherwise.
+func reportMaxBytes(e env) int {
+	if v, ok := e.getenv("CROSTC_REPORT_MAX_BYTES"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return defaultReportMaxBytes
+}
+
+// truncateCapturedOutput caps data at reportMaxBytes(e), appending
+// reportTruncatedMarker when it does. Output under the cap is returned
+// unchanged.
+func truncateCapturedOutput(e env, data string) string {
+	max := reportMaxBytes(e)
+	if len(data) <= max {
+		return data
+	}
+	cut := max - len(reportTruncatedMarker)
+	if cut < 0 {
+		cut = 0
+	}
+	return data[:cut] + reportTruncatedMarker
+}
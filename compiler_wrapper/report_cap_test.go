@@ -0,0 +1,45 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReportMaxBytesDefault(t *testing.T) {
+	if got := reportMaxBytes(newFakeEnv(map[string]string{})); got != defaultReportMaxBytes {
+		t.Errorf("reportMaxBytes = %d, want %d", got, defaultReportMaxBytes)
+	}
+}
+
+func TestReportMaxBytesOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_MAX_BYTES": "100"})
+	if got := reportMaxBytes(e); got != 100 {
+		t.Errorf("reportMaxBytes = %d, want 100", got)
+	}
+}
+
+func TestReportMaxBytesIgnoresInvalidOverride(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_MAX_BYTES": "not-a-number"})
+	if got := reportMaxBytes(e); got != defaultReportMaxBytes {
+		t.Errorf("reportMaxBytes = %d, want default %d", got, defaultReportMaxBytes)
+	}
+}
+
+func TestTruncateCapturedOutputTruncatesOversizedOutput(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_MAX_BYTES": "50"})
+	got := truncateCapturedOutput(e, strings.Repeat("x", 1000))
+	if len(got) > 50 {
+		t.Errorf("got length %d, want <= 50", len(got))
+	}
+	if !strings.HasSuffix(got, reportTruncatedMarker) {
+		t.Errorf("expected truncation marker, got %q", got)
+	}
+}
+
+func TestTruncateCapturedOutputLeavesSmallOutputAlone(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	got := truncateCapturedOutput(e, "short output")
+	if got != "short output" {
+		t.Errorf("got %q, want unchanged", got)
+	}
+}
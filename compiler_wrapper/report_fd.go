@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// androidReportWriter returns the destination for the wrapper's Android
+// llvm-next report: the file descriptor named by LLVM_NEXT_REPORT_FD, if
+// set, so a remote build that also uses stdout for other data isn't
+// corrupted by the report landing there too. Falls back to stdout when
+// LLVM_NEXT_REPORT_FD is unset.
+func androidReportWriter(e env) (*os.File, error) {
+	v, ok := e.getenv("LLVM_NEXT_REPORT_FD")
+	if !ok {
+		return os.Stdout, nil
+	}
+	fd, err := strconv.Atoi(v)
+	if err != nil {
+		return nil, fmt.Errorf("LLVM_NEXT_REPORT_FD %q is not a valid file descriptor: %w", v, err)
+	}
+	return os.NewFile(uintptr(fd), "llvm-next-report"), nil
+}
+
+// writeAndroidReport writes report to androidReportWriter's destination.
+func writeAndroidReport(e env, report string) error {
+	f, err := androidReportWriter(e)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprint(f, report)
+	return err
+}
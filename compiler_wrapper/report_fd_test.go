@@ -0,0 +1,41 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestWriteAndroidReportUsesConfiguredFD(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	defer r.Close()
+
+	e := newFakeEnv(map[string]string{"LLVM_NEXT_REPORT_FD": strconv.Itoa(int(w.Fd()))})
+	if err := writeAndroidReport(e, "hello report"); err != nil {
+		t.Fatalf("writeAndroidReport: %v", err)
+	}
+	w.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading pipe: %v", err)
+	}
+	if string(got) != "hello report" {
+		t.Errorf("got %q, want %q", got, "hello report")
+	}
+}
+
+func TestAndroidReportWriterDefaultsToStdout(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	f, err := androidReportWriter(e)
+	if err != nil {
+		t.Fatalf("androidReportWriter: %v", err)
+	}
+	if f != os.Stdout {
+		t.Error("expected the default writer to be os.Stdout")
+	}
+}
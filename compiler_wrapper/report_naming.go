@@ -0,0 +1,65 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"strconv"
+)
+
+const reportNameSchemeEnvVar = "CROSTC_REPORT_NAME_SCHEME"
+
+const (
+	reportNameSchemeHash       = "hash"
+	reportNameSchemeSequential = "sequential"
+	reportNameSchemeRandom     = "random"
+)
+
+// reportNameScheme returns the configured report filename scheme, falling
+// back to reportNameSchemeHash, this tree's existing behavior (the warnings
+// and opt-record reports are already named by a hash of their output path).
+func reportNameScheme(e env) string {
+	if v, ok := e.getenv(reportNameSchemeEnvVar); ok && v != "" {
+		return v
+	}
+	return reportNameSchemeHash
+}
+
+// randomReportName generates an unpredictable filename-safe name, for the
+// "random" scheme.
+func randomReportName() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// nextSequentialReportName returns the count of entries already in dir, for
+// the "sequential" scheme. It's a best-effort counter, not concurrency-safe
+// across simultaneous invocations, which is acceptable for a debugging aid.
+func nextSequentialReportName(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "0", nil
+		}
+		return "", err
+	}
+	return strconv.Itoa(len(entries)), nil
+}
+
+// reportName picks the base filename (no extension) for a report written to
+// dir about output, according to the configured scheme. The "hash" scheme
+// enables deduping identical failures, since identical output paths always
+// hash to the same name.
+func reportName(e env, dir, output string) (string, error) {
+	switch reportNameScheme(e) {
+	case reportNameSchemeSequential:
+		return nextSequentialReportName(dir)
+	case reportNameSchemeRandom:
+		return randomReportName()
+	default:
+		return randomSeedForOutput(output), nil
+	}
+}
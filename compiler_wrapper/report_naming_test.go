@@ -0,0 +1,92 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestReportNameDefaultsToHash(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{})
+
+	got, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := randomSeedForOutput("foo.o")
+	if got != want {
+		t.Errorf("reportName = %q, want %q", got, want)
+	}
+}
+
+func TestReportNameHashIsDeterministic(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_NAME_SCHEME": "hash"})
+
+	a, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != b {
+		t.Errorf("expected hash scheme to be deterministic, got %q and %q", a, b)
+	}
+}
+
+func TestReportNameRandomVariesPerCall(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_NAME_SCHEME": "random"})
+
+	a, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a == b {
+		t.Error("expected random scheme to produce different names across calls")
+	}
+}
+
+func TestReportNameSequentialCountsExistingEntries(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_NAME_SCHEME": "sequential"})
+
+	first, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != "0" {
+		t.Errorf("first sequential name = %q, want 0", first)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, first+".warnings.txt"), []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := reportName(e, dir, "foo.o")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second != "1" {
+		t.Errorf("second sequential name = %q, want 1", second)
+	}
+}
+
+func TestReportNameSequentialHandlesMissingDir(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_REPORT_NAME_SCHEME": "sequential"})
+
+	got, err := reportName(e, filepath.Join(t.TempDir(), "missing"), "foo.o")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != "0" {
+		t.Errorf("reportName = %q, want 0", got)
+	}
+}
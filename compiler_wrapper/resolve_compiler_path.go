@@ -0,0 +1,24 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// resolveAbsCompilerPath resolves path, an absolute path the wrapper was
+// itself invoked as (e.g. a board-specific symlink into a toolchain
+// prefix), through any symlinks along the way. filepath.EvalSymlinks's own
+// error for a broken link partway through the chain is an opaque
+// os.PathError that doesn't say which link was broken or what it pointed
+// at; this wraps it with both, since that's what actually needs fixing.
+func resolveAbsCompilerPath(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if target, readErr := os.Readlink(path); readErr == nil {
+		return "", fmt.Errorf("compiler path %q is a broken symlink pointing at %q: %w", path, target, err)
+	}
+	return "", err
+}
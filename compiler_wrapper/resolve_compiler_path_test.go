@@ -0,0 +1,45 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveAbsCompilerPathFollowsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	real := filepath.Join(dir, "real-gcc")
+	if err := os.WriteFile(real, []byte(""), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "gcc")
+	if err := os.Symlink(real, link); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := resolveAbsCompilerPath(link)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != real {
+		t.Errorf("got %q, want %q", got, real)
+	}
+}
+
+func TestResolveAbsCompilerPathDanglingSymlinkError(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "does-not-exist")
+	link := filepath.Join(dir, "gcc")
+	if err := os.Symlink(missing, link); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := resolveAbsCompilerPath(link)
+	if err == nil {
+		t.Fatal("expected an error for a dangling symlink")
+	}
+	if !strings.Contains(err.Error(), link) || !strings.Contains(err.Error(), missing) {
+		t.Errorf("expected error to name both the symlink and its target, got: %v", err)
+	}
+}
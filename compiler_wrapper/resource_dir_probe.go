@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultResourceDirProbeTimeout bounds how long a single -print-resource-dir
+// probe attempt may run before being treated as hung.
+const defaultResourceDirProbeTimeout = 5 * time.Second
+
+// resourceDirProbeTimeout reads CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS,
+// falling back to defaultResourceDirProbeTimeout.
+func resourceDirProbeTimeout(e env) time.Duration {
+	if v, ok := e.getenv("CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS"); ok {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return time.Duration(n) * time.Second
+		}
+	}
+	return defaultResourceDirProbeTimeout
+}
+
+// runResourceDirProbeFunc runs a single -print-resource-dir attempt,
+// overridable in tests so they don't need a real compiler binary.
+var runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+	args := append(append([]string{}, extraArgs...), "-print-resource-dir")
+	cmd := exec.CommandContext(ctx, compilerPath, args...)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// probeResourceDir runs -print-resource-dir with a timeout, retrying once
+// if the first attempt times out. extraArgs are carried along (deduped via
+// dedupTargetFlags, in case a caller-supplied -target collides with one the
+// wrapper already added) since clang resolves its resource dir relative to
+// the effective target, which can differ from the host's default. The
+// probe occasionally hangs on an overloaded remote filesystem, and a
+// wedged compile is worse than the extra latency of one retry; if both
+// attempts time out, it fails with a clear error rather than hanging
+// indefinitely.
+func probeResourceDir(e env, compilerPath string, extraArgs []string) (string, error) {
+	args := dedupTargetFlags(extraArgs)
+	timeout := resourceDirProbeTimeout(e)
+	const maxAttempts = 2
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		dir, err := runResourceDirProbeFunc(ctx, compilerPath, args)
+		cancel()
+		if err == nil {
+			return dir, nil
+		}
+		if ctx.Err() != context.DeadlineExceeded {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("resource-dir probe for %q timed out after %d attempts (%s each)", compilerPath, maxAttempts, timeout)
+}
+
+// maybeInjectResourceDir probes compilerCmd's compiler for its resource dir
+// (passing target along, so the probe resolves the same resource dir the
+// real compile would use) and appends it as an explicit -resource-dir flag,
+// when cfg.ProbeResourceDir opts in. A failed probe is swallowed rather than
+// failing the build: a wrong or missing resource dir surfaces as its own
+// clear compiler error downstream, which is a better failure mode than the
+// wrapper itself refusing to compile over a probe hiccup.
+func maybeInjectResourceDir(e env, cfg *config, target string, compilerCmd *command) *command {
+	if !cfg.ProbeResourceDir {
+		return compilerCmd
+	}
+	dir, err := probeResourceDir(e, compilerCmd.Path, []string{"-target", target})
+	if err != nil {
+		logDebugf(e, "resource-dir probe failed, leaving clang to resolve its own: %v", err)
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-resource-dir="+dir)
+	return &newCmd
+}
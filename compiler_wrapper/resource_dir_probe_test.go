@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProbeResourceDirSucceedsFirstTry(t *testing.T) {
+	orig := runResourceDirProbeFunc
+	defer func() { runResourceDirProbeFunc = orig }()
+
+	calls := 0
+	runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+		calls++
+		return "/usr/lib/clang/17", nil
+	}
+
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "1"})
+	dir, err := probeResourceDir(e, "/usr/bin/clang", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dir != "/usr/lib/clang/17" {
+		t.Errorf("got %q, want /usr/lib/clang/17", dir)
+	}
+	if calls != 1 {
+		t.Errorf("expected exactly 1 call, got %d", calls)
+	}
+}
+
+func TestProbeResourceDirDedupsExtraArgsTargetFlags(t *testing.T) {
+	orig := runResourceDirProbeFunc
+	defer func() { runResourceDirProbeFunc = orig }()
+
+	var gotArgs []string
+	runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+		gotArgs = extraArgs
+		return "/usr/lib/clang/17", nil
+	}
+
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "1"})
+	extraArgs := []string{"-target", "arm-linux-gnueabihf", "-target", "x86_64-linux-gnu"}
+	if _, err := probeResourceDir(e, "/usr/bin/clang", extraArgs); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"-target", "x86_64-linux-gnu"}
+	if len(gotArgs) != len(want) || gotArgs[0] != want[0] || gotArgs[1] != want[1] {
+		t.Errorf("got extraArgs %v, want %v (deduped to the last -target)", gotArgs, want)
+	}
+}
+
+func TestProbeResourceDirHangsOnceThenSucceeds(t *testing.T) {
+	orig := runResourceDirProbeFunc
+	defer func() { runResourceDirProbeFunc = orig }()
+
+	calls := 0
+	runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+		calls++
+		if calls == 1 {
+			// Simulate a hung probe: block until the context's deadline
+			// fires rather than returning.
+			<-ctx.Done()
+			return "", ctx.Err()
+		}
+		return "/usr/lib/clang/17", nil
+	}
+
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "1"})
+	start := time.Now()
+	dir, err := probeResourceDir(e, "/usr/bin/clang", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if dir != "/usr/lib/clang/17" {
+		t.Errorf("got %q, want /usr/lib/clang/17", dir)
+	}
+	if calls != 2 {
+		t.Errorf("expected exactly 2 calls (one hang, one retry), got %d", calls)
+	}
+	if elapsed := time.Since(start); elapsed < 1*time.Second {
+		t.Errorf("expected the hung attempt to consume its full timeout, elapsed %s", elapsed)
+	}
+}
+
+func TestProbeResourceDirFailsClearlyAfterTwoTimeouts(t *testing.T) {
+	orig := runResourceDirProbeFunc
+	defer func() { runResourceDirProbeFunc = orig }()
+
+	calls := 0
+	runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+		calls++
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "1"})
+	_, err := probeResourceDir(e, "/usr/bin/clang", nil)
+	if err == nil {
+		t.Fatal("expected an error after both attempts timed out")
+	}
+	if calls != 2 {
+		t.Errorf("expected exactly 2 attempts, got %d", calls)
+	}
+}
+
+func TestProbeResourceDirPropagatesNonTimeoutError(t *testing.T) {
+	orig := runResourceDirProbeFunc
+	defer func() { runResourceDirProbeFunc = orig }()
+
+	wantErr := errors.New("no such file or directory")
+	calls := 0
+	runResourceDirProbeFunc = func(ctx context.Context, compilerPath string, extraArgs []string) (string, error) {
+		calls++
+		return "", wantErr
+	}
+
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "1"})
+	_, err := probeResourceDir(e, "/usr/bin/clang", nil)
+	if !errors.Is(err, wantErr) {
+		t.Errorf("got error %v, want %v", err, wantErr)
+	}
+	if calls != 1 {
+		t.Errorf("expected no retry on a non-timeout error, got %d calls", calls)
+	}
+}
+
+func TestResourceDirProbeTimeoutDefaultsWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if got := resourceDirProbeTimeout(e); got != defaultResourceDirProbeTimeout {
+		t.Errorf("got %s, want %s", got, defaultResourceDirProbeTimeout)
+	}
+}
+
+func TestResourceDirProbeTimeoutIgnoresInvalidValue(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_RESOURCE_DIR_PROBE_TIMEOUT_SECONDS": "not-a-number"})
+	if got := resourceDirProbeTimeout(e); got != defaultResourceDirProbeTimeout {
+		t.Errorf("got %s, want %s", got, defaultResourceDirProbeTimeout)
+	}
+}
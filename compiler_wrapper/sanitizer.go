@@ -0,0 +1,75 @@
+package main
+
+import "strings"
+
+// isSanitizerEnabled reports whether compilerCmd requests any sanitizer via
+// -fsanitize=.
+func isSanitizerEnabled(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-fsanitize=") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSharedFlag reports whether compilerCmd links a shared library.
+func hasSharedFlag(compilerCmd *command) bool {
+	for _, arg := range compilerCmd.Args {
+		if arg == "-shared" {
+			return true
+		}
+	}
+	return false
+}
+
+// processSanitizerLinkFlags drops -Wl,-z,defs for sanitizer-instrumented
+// shared-library links: sanitizer runtimes pull in symbols that aren't
+// resolvable at link time, and -z,defs requires every symbol to resolve.
+// Static (non-shared) binaries aren't affected by that and keep the flag,
+// since it's harmless there and sometimes desired.
+func processSanitizerLinkFlags(compilerCmd *command) *command {
+	if !isLinkOnly(compilerCmd) || !isSanitizerEnabled(compilerCmd) || !hasSharedFlag(compilerCmd) {
+		return compilerCmd
+	}
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		if arg == "-Wl,-z,defs" {
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// processSanitizerFlags drops -Wl,--no-undefined when -fsanitize is
+// present, since sanitizer instrumentation can pull in runtime symbols
+// that aren't resolvable until the sanitizer runtime is loaded, and
+// -Wl,--no-undefined would turn that into a link failure. Link-only
+// invocations are left untouched: there, callers often need
+// -Wl,--no-undefined to hold for correct runtime linkage, and dropping it
+// out from under them would be unwanted.
+func processSanitizerFlags(compilerCmd *command) *command {
+	if !isSanitizerEnabled(compilerCmd) || isLinkOnly(compilerCmd) {
+		return compilerCmd
+	}
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	changed := false
+	for _, arg := range compilerCmd.Args {
+		if arg == "-Wl,--no-undefined" {
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, arg)
+	}
+	if !changed {
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
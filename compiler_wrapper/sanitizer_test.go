@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestProcessSanitizerLinkFlagsDropsDefsForSharedSanitizerLink(t *testing.T) {
+	cmd := &command{Args: []string{"-shared", "-fsanitize=address", "-Wl,-z,defs", "-o", "libfoo.so"}}
+
+	got := processSanitizerLinkFlags(cmd)
+
+	for _, a := range got.Args {
+		if a == "-Wl,-z,defs" {
+			t.Errorf("expected -Wl,-z,defs to be dropped, got %v", got.Args)
+		}
+	}
+}
+
+func TestProcessSanitizerLinkFlagsKeepsDefsForNonSharedCompile(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-fsanitize=address", "-Wl,-z,defs", "foo.c"}}
+
+	got := processSanitizerLinkFlags(cmd)
+
+	if got != cmd {
+		t.Error("expected a non-shared sanitizer compile to be left untouched")
+	}
+}
+
+func TestProcessSanitizerLinkFlagsKeepsDefsForStaticSanitizerLink(t *testing.T) {
+	cmd := &command{Args: []string{"-fsanitize=address", "-Wl,-z,defs", "-o", "a.out", "foo.o"}}
+
+	got := processSanitizerLinkFlags(cmd)
+
+	if got != cmd {
+		t.Error("expected a static sanitizer link (no -shared) to be left untouched")
+	}
+}
+
+func TestProcessSanitizerFlagsDropsNoUndefinedForSanitizerCompile(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-fsanitize=address", "-Wl,--no-undefined", "foo.c"}}
+
+	got := processSanitizerFlags(cmd)
+
+	for _, a := range got.Args {
+		if a == "-Wl,--no-undefined" {
+			t.Errorf("expected -Wl,--no-undefined to be dropped, got %v", got.Args)
+		}
+	}
+}
+
+func TestProcessSanitizerFlagsKeepsNoUndefinedForSanitizerLink(t *testing.T) {
+	cmd := &command{Args: []string{"-fsanitize=address", "-Wl,--no-undefined", "-o", "a.out", "foo.o"}}
+
+	got := processSanitizerFlags(cmd)
+
+	if got != cmd {
+		t.Error("expected a link-only sanitizer invocation to keep -Wl,--no-undefined")
+	}
+}
+
+func TestProcessSanitizerFlagsNoopWithoutSanitizer(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wl,--no-undefined", "foo.c"}}
+
+	got := processSanitizerFlags(cmd)
+
+	if got != cmd {
+		t.Error("expected no change without -fsanitize")
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// saveInvocationDirEnvVar names the directory invocation-reproduction
+// scripts are written to, if set.
+const saveInvocationDirEnvVar = "COMPILER_WRAPPER_SAVE_INVOCATION_DIR"
+
+// quoteShellArg quotes arg for safe reuse inside a POSIX shell script,
+// wrapping it in single quotes and escaping any single quotes it contains.
+func quoteShellArg(arg string) string {
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// renderReproductionScript renders a POSIX shell script that reproduces
+// compilerCmd exactly: the working directory it ran in, any environment
+// overrides it carried, and the argv itself.
+func renderReproductionScript(cwd string, compilerCmd *command) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "cd %s\n", quoteShellArg(cwd))
+
+	keys := make([]string, 0, len(compilerCmd.EnvUpdates))
+	for k := range compilerCmd.EnvUpdates {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, "export %s=%s\n", k, quoteShellArg(compilerCmd.EnvUpdates[k]))
+	}
+
+	b.WriteString(quoteShellArg(compilerCmd.Path))
+	for _, arg := range compilerCmd.Args {
+		b.WriteByte(' ')
+		b.WriteString(quoteShellArg(arg))
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// maybeSaveInvocation writes a reproduction script for compilerCmd into
+// COMPILER_WRAPPER_SAVE_INVOCATION_DIR, if set, named after output, so a
+// failing compile's exact command can be replayed with one click. It's a
+// no-op if the var isn't set; write errors are swallowed since this is a
+// best-effort debugging aid, not something that should fail a build.
+func maybeSaveInvocation(e env, cwd, output string, compilerCmd *command) {
+	dir, ok := e.getenv(saveInvocationDirEnvVar)
+	if !ok || dir == "" {
+		return
+	}
+	path := filepath.Join(dir, randomSeedForOutput(output)+".sh")
+	script := renderReproductionScript(effectiveWorkingDir(cwd, compilerCmd), compilerCmd)
+	os.WriteFile(path, []byte(script), 0o755)
+}
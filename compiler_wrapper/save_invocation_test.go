@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQuoteShellArgEscapesSingleQuotes(t *testing.T) {
+	got := quoteShellArg("it's")
+	want := `'it'\''s'`
+	if got != want {
+		t.Errorf("quoteShellArg = %q, want %q", got, want)
+	}
+}
+
+func TestRenderReproductionScriptRoundTrips(t *testing.T) {
+	cmd := &command{
+		Path:       "/usr/bin/gcc",
+		Args:       []string{"-c", "foo's.c", "-o", "foo.o"},
+		EnvUpdates: map[string]string{"CROSTC_TARGET": "arm"},
+	}
+
+	script := renderReproductionScript("/build/dir", cmd)
+
+	if !strings.Contains(script, "cd '/build/dir'") {
+		t.Errorf("expected cwd line, got:\n%s", script)
+	}
+	if !strings.Contains(script, "export CROSTC_TARGET='arm'") {
+		t.Errorf("expected env export line, got:\n%s", script)
+	}
+	if !strings.Contains(script, "'/usr/bin/gcc' '-c' 'foo'\\''s.c' '-o' 'foo.o'") {
+		t.Errorf("expected quoted argv line, got:\n%s", script)
+	}
+}
+
+func TestMaybeSaveInvocationWritesScript(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{saveInvocationDirEnvVar: dir})
+	cmd := &command{Path: "/usr/bin/gcc", Args: []string{"-c", "foo.c"}}
+
+	maybeSaveInvocation(e, "/build/dir", "foo.o", cmd)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d files, want 1", len(entries))
+	}
+	contents, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(contents), "/usr/bin/gcc") {
+		t.Errorf("expected script to contain compiler path, got:\n%s", contents)
+	}
+}
+
+func TestMaybeSaveInvocationNoopWhenUnset(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Path: "/usr/bin/gcc", Args: []string{"-c", "foo.c"}}
+
+	maybeSaveInvocation(e, "/build/dir", "foo.o", cmd)
+}
@@ -0,0 +1,27 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsWrapperSelftestCommand(t *testing.T) {
+	if !isWrapperSelftestCommand(&command{Args: []string{"--wrapper-selftest"}}) {
+		t.Error("expected --wrapper-selftest to be recognized")
+	}
+	if isWrapperSelftestCommand(&command{Args: []string{"-c", "foo.c"}}) {
+		t.Error("expected an ordinary compile not to be recognized")
+	}
+}
+
+func TestRunWrapperSelftestPassesForKnownGoodConfig(t *testing.T) {
+	report, ok := runWrapperSelftest()
+	if !ok {
+		t.Errorf("expected selftest to pass, got report:\n%s", report)
+	}
+	for _, name := range []string{"config loads", "target parses", "flag pipeline"} {
+		if !strings.Contains(report, "PASS: "+name) {
+			t.Errorf("expected report to contain %q, got:\n%s", "PASS: "+name, report)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// signalForwardGrace bounds how long runWithSignalForwarding waits for a
+// forwarded signal to take effect before it gives up on a graceful exit and
+// lets the caller's own process death race ahead.
+const signalForwardGrace = 2 * time.Second
+
+// runWithSignalForwarding starts cmd and forwards SIGINT/SIGTERM received
+// by the wrapper itself to cmd's process for as long as it's running, so
+// Ctrl-C during a run-based step (double-build, and friends) doesn't leave
+// the child running detached from a wrapper that already exited. It
+// returns cmd.Wait's result.
+func runWithSignalForwarding(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	done := make(chan struct{})
+	go func() {
+		select {
+		case sig := <-sigCh:
+			cmd.Process.Signal(sig)
+			select {
+			case <-done:
+			case <-time.After(signalForwardGrace):
+			}
+		case <-done:
+		}
+	}()
+
+	err := cmd.Wait()
+	close(done)
+	return err
+}
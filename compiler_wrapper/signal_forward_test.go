@@ -0,0 +1,38 @@
+//go:build linux
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestRunWithSignalForwardingTerminatesChildOnSIGTERM(t *testing.T) {
+	cmd := exec.Command("sleep", "30")
+
+	done := make(chan error, 1)
+	go func() { done <- runWithSignalForwarding(cmd) }()
+
+	// Give the child a moment to start before signaling the wrapper.
+	time.Sleep(100 * time.Millisecond)
+	if err := syscall.Kill(os.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("sending SIGTERM to self: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatalf("expected an *exec.ExitError, got %v", err)
+		}
+		ws := exitErr.Sys().(syscall.WaitStatus)
+		if !ws.Signaled() || ws.Signal() != syscall.SIGTERM {
+			t.Errorf("expected the child to have been killed by SIGTERM, got %v", ws)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the forwarded signal to terminate the child")
+	}
+}
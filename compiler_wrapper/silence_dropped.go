@@ -0,0 +1,28 @@
+package main
+
+// silenceDroppedEnvVar opts into silencing clang's "unused command line
+// argument" warning for flags the wrapper itself dropped, rather than
+// flags the user passed that clang legitimately never used.
+const silenceDroppedEnvVar = "COMPILER_WRAPPER_SILENCE_DROPPED"
+
+// silenceDroppedEnabled reports whether COMPILER_WRAPPER_SILENCE_DROPPED=1
+// is set.
+func silenceDroppedEnabled(e env) bool {
+	v, ok := e.getenv(silenceDroppedEnvVar)
+	return ok && v == "1"
+}
+
+// processSilenceDropped appends -Wno-unused-command-line-argument for
+// clang when droppedFlags reports that some earlier step in the wrapper
+// removed a user-supplied flag, so the user isn't shown a spurious warning
+// about a flag they never even see reach the compiler. It's a no-op unless
+// COMPILER_WRAPPER_SILENCE_DROPPED=1 is set, nothing was actually dropped,
+// or the compiler is gcc, which has no equivalent warning to silence.
+func processSilenceDropped(e env, compilerIsClang bool, droppedFlags bool, compilerCmd *command) *command {
+	if !compilerIsClang || !droppedFlags || !silenceDroppedEnabled(e) {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = append(append([]string{}, compilerCmd.Args...), "-Wno-unused-command-line-argument")
+	return &newCmd
+}
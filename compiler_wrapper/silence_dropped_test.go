@@ -0,0 +1,47 @@
+package main
+
+import "testing"
+
+func TestProcessSilenceDroppedInjectsWhenFlagsWereDropped(t *testing.T) {
+	e := newFakeEnv(map[string]string{silenceDroppedEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processSilenceDropped(e, true, true, cmd)
+
+	if got.Args[len(got.Args)-1] != "-Wno-unused-command-line-argument" {
+		t.Errorf("expected the flag to be injected, got %v", got.Args)
+	}
+}
+
+func TestProcessSilenceDroppedNoopWhenNothingDropped(t *testing.T) {
+	e := newFakeEnv(map[string]string{silenceDroppedEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processSilenceDropped(e, true, false, cmd)
+
+	if got != cmd {
+		t.Error("expected no injection when the wrapper dropped nothing")
+	}
+}
+
+func TestProcessSilenceDroppedNoopWhenDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processSilenceDropped(e, true, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no injection when COMPILER_WRAPPER_SILENCE_DROPPED is unset")
+	}
+}
+
+func TestProcessSilenceDroppedNoopForGcc(t *testing.T) {
+	e := newFakeEnv(map[string]string{silenceDroppedEnvVar: "1"})
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+
+	got := processSilenceDropped(e, false, true, cmd)
+
+	if got != cmd {
+		t.Error("expected no injection for gcc")
+	}
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const specsFlagPrefix = "-specs="
+
+// processGCCSpecsFlag rewrites an absolute -specs=<path> argument to be
+// relative to cfg.Root, so the resulting command line doesn't leak the local
+// build root into remote/reproducible builds. It only applies when path
+// relativization is enabled and the real compiler is gcc; clang doesn't
+// support -specs.
+func processGCCSpecsFlag(cfg *config, compilerIsGcc bool, compilerCmd *command) (*command, error) {
+	if !cfg.RelativizePaths || !compilerIsGcc {
+		return compilerCmd, nil
+	}
+
+	newArgs := make([]string, len(compilerCmd.Args))
+	copy(newArgs, compilerCmd.Args)
+	for i, arg := range newArgs {
+		specsPath, isSpecs := strings.CutPrefix(arg, specsFlagPrefix)
+		if !isSpecs {
+			continue
+		}
+		rel, ok := rootRelative(cfg.Root, specsPath)
+		if !ok {
+			// Relative already, or outside of root: pass through unchanged.
+			continue
+		}
+		if _, err := os.Stat(specsPath); err != nil {
+			return nil, fmt.Errorf("-specs file %q: %w", specsPath, err)
+		}
+		newArgs[i] = specsFlagPrefix + rel
+	}
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd, nil
+}
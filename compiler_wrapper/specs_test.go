@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestProcessGCCSpecsFlagRewritesToRelative(t *testing.T) {
+	root := t.TempDir()
+	specsPath := filepath.Join(root, "gcc.specs")
+	if err := os.WriteFile(specsPath, []byte(""), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{"-c", "foo.c", specsFlagPrefix + specsPath}}
+
+	got, err := processGCCSpecsFlag(cfg, true, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := specsFlagPrefix + "gcc.specs"
+	if got.Args[2] != want {
+		t.Errorf("Args[2] = %q, want %q", got.Args[2], want)
+	}
+}
+
+func TestProcessGCCSpecsFlagMissingFileErrors(t *testing.T) {
+	root := t.TempDir()
+	missing := filepath.Join(root, "missing.specs")
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{specsFlagPrefix + missing}}
+
+	if _, err := processGCCSpecsFlag(cfg, true, cmd); err == nil {
+		t.Fatal("expected an error for a missing specs file")
+	}
+}
+
+func TestProcessGCCSpecsFlagOutsideRootPassesThrough(t *testing.T) {
+	root := t.TempDir()
+	outside := "/some/other/path.specs"
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{specsFlagPrefix + outside}}
+
+	got, err := processGCCSpecsFlag(cfg, true, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Args[0] != specsFlagPrefix+outside {
+		t.Errorf("Args[0] = %q, want unchanged %q", got.Args[0], specsFlagPrefix+outside)
+	}
+}
+
+func TestProcessGCCSpecsFlagClangPassesThrough(t *testing.T) {
+	root := t.TempDir()
+	cfg := &config{Root: root, RelativizePaths: true}
+	cmd := &command{Args: []string{specsFlagPrefix + filepath.Join(root, "x.specs")}}
+
+	got, err := processGCCSpecsFlag(cfg, false, cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != cmd {
+		t.Error("expected clang invocations to be returned unmodified")
+	}
+}
@@ -0,0 +1,26 @@
+package main
+
+import "strings"
+
+// checkStdFlag compares a -std= argument against the driver language and
+// returns a userError when they're clearly mismatched (a C standard passed
+// to a C++ compile, or vice versa), behind COMPILER_WRAPPER_CHECK_STD=1.
+func checkStdFlag(e env, isCxxDriver bool, compilerCmd *command) error {
+	if _, ok := e.getenv("COMPILER_WRAPPER_CHECK_STD"); !ok {
+		return nil
+	}
+	for _, arg := range compilerCmd.Args {
+		std, ok := strings.CutPrefix(arg, "-std=")
+		if !ok {
+			continue
+		}
+		isCxxStd := strings.Contains(std, "++")
+		switch {
+		case isCxxDriver && !isCxxStd:
+			return newUserError("passing a C standard (%s) to a C++ compile", arg)
+		case !isCxxDriver && isCxxStd:
+			return newUserError("passing a C++ standard (%s) to a C compile", arg)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestCheckStdFlagMismatch(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_CHECK_STD": "1"})
+
+	if err := checkStdFlag(e, true, &command{Args: []string{"-std=c11", "foo.cc"}}); !isUserError(err) {
+		t.Errorf("expected a userError for -std=c11 on a C++ compile, got %v", err)
+	}
+	if err := checkStdFlag(e, false, &command{Args: []string{"-std=c++17", "foo.c"}}); !isUserError(err) {
+		t.Errorf("expected a userError for -std=c++17 on a C compile, got %v", err)
+	}
+}
+
+func TestCheckStdFlagMatchingPasses(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_CHECK_STD": "1"})
+
+	if err := checkStdFlag(e, true, &command{Args: []string{"-std=c++17", "foo.cc"}}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+	if err := checkStdFlag(e, false, &command{Args: []string{"-std=gnu11", "foo.c"}}); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+func TestCheckStdFlagDisabledByDefault(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if err := checkStdFlag(e, true, &command{Args: []string{"-std=c11", "foo.cc"}}); err != nil {
+		t.Errorf("expected no check without COMPILER_WRAPPER_CHECK_STD, got %v", err)
+	}
+}
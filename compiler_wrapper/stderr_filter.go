@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"regexp"
+)
+
+// stderrFilterEnabled reports whether COMPILER_WRAPPER_STDERR_FILTER_REGEX
+// is set, and returns the compiled pattern when it is.
+func stderrFilterEnabled(e env) (*regexp.Regexp, bool) {
+	pattern, ok := e.getenv("COMPILER_WRAPPER_STDERR_FILTER_REGEX")
+	if !ok || pattern == "" {
+		return nil, false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, false
+	}
+	return re, true
+}
+
+// filteringWriter is a line-buffering io.Writer that drops any line
+// matching drop before forwarding the rest to out. Lines are delimited by
+// '\n', which is preserved on forwarded lines; a final unterminated line is
+// flushed as-is when the writer is closed.
+type filteringWriter struct {
+	out  io.Writer
+	drop *regexp.Regexp
+	buf  []byte
+}
+
+func newFilteringWriter(out io.Writer, drop *regexp.Regexp) *filteringWriter {
+	return &filteringWriter{out: out, drop: drop}
+}
+
+func (w *filteringWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+		line := w.buf[:i+1]
+		w.buf = w.buf[i+1:]
+		if w.drop.Match(line[:len(line)-1]) {
+			continue
+		}
+		if _, err := w.out.Write(line); err != nil {
+			return len(p), err
+		}
+	}
+	return len(p), nil
+}
+
+// Close flushes any buffered partial line that was never terminated by a
+// trailing newline.
+func (w *filteringWriter) Close() error {
+	if len(w.buf) == 0 {
+		return nil
+	}
+	line := w.buf
+	w.buf = nil
+	if w.drop.Match(line) {
+		return nil
+	}
+	_, err := w.out.Write(line)
+	return err
+}
+
+// runFilteringStderr runs compilerCmd as a subprocess, forwarding its
+// stdout unmodified and its stderr through a filteringWriter that drops
+// lines matching drop. It returns the subprocess's exit code and any error
+// launching or waiting for it; a non-zero exit from the compiler itself is
+// not reported as an error.
+func runFilteringStderr(compilerCmd *command, stdout, stderr io.Writer, drop *regexp.Regexp) (int, error) {
+	cmd := exec.Command(compilerCmd.Path, compilerCmd.Args...)
+	cmd.Stdout = stdout
+	setNewProcessGroup(cmd)
+
+	filtered := newFilteringWriter(stderr, drop)
+	cmd.Stderr = filtered
+
+	runErr := runWithSignalForwarding(cmd)
+	filtered.Close()
+
+	if exitErr, ok := runErr.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	if runErr != nil {
+		return -1, runErr
+	}
+	return 0, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+)
+
+func TestRunFilteringStderrDropsMatchingLinesFromRealProcess(t *testing.T) {
+	cmd := &command{
+		Path: "/bin/sh",
+		Args: []string{"-c", "echo to stdout; echo noisy linker note >&2; echo real warning >&2"},
+	}
+
+	var stdout, stderr bytes.Buffer
+	code, err := runFilteringStderr(cmd, &stdout, &stderr, regexp.MustCompile("^noisy"))
+	if err != nil {
+		t.Fatalf("runFilteringStderr: %v", err)
+	}
+	if code != 0 {
+		t.Errorf("expected exit code 0, got %d", code)
+	}
+	if got, want := stdout.String(), "to stdout\n"; got != want {
+		t.Errorf("stdout = %q, want %q", got, want)
+	}
+	if got, want := stderr.String(), "real warning\n"; got != want {
+		t.Errorf("stderr = %q, want %q", got, want)
+	}
+}
+
+func TestRunFilteringStderrPropagatesExitCode(t *testing.T) {
+	cmd := &command{Path: "/bin/sh", Args: []string{"-c", "exit 7"}}
+
+	var stdout, stderr bytes.Buffer
+	code, err := runFilteringStderr(cmd, &stdout, &stderr, regexp.MustCompile("^noisy"))
+	if err != nil {
+		t.Fatalf("runFilteringStderr: %v", err)
+	}
+	if code != 7 {
+		t.Errorf("expected exit code 7, got %d", code)
+	}
+}
+
+func TestStderrFilterEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_STDERR_FILTER_REGEX": "^note:"})
+	re, ok := stderrFilterEnabled(e)
+	if !ok || re == nil {
+		t.Fatalf("expected filter to be enabled")
+	}
+
+	if _, ok := stderrFilterEnabled(newFakeEnv(map[string]string{})); ok {
+		t.Errorf("expected filter to be disabled when unset")
+	}
+}
+
+func TestStderrFilterEnabledInvalidRegexIsDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_STDERR_FILTER_REGEX": "("})
+	if _, ok := stderrFilterEnabled(e); ok {
+		t.Errorf("expected an invalid regex to disable filtering rather than panic")
+	}
+}
+
+func TestFilteringWriterDropsMatchingLines(t *testing.T) {
+	var out bytes.Buffer
+	w := newFilteringWriter(&out, regexp.MustCompile("^noisy linker note"))
+
+	if _, err := w.Write([]byte("noisy linker note: ignore me\nreal warning: pay attention\n")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	want := "real warning: pay attention\n"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFilteringWriterPreservesNonMatchingAndPartialLines(t *testing.T) {
+	var out bytes.Buffer
+	w := newFilteringWriter(&out, regexp.MustCompile("^noisy"))
+
+	w.Write([]byte("line one\nline "))
+	w.Write([]byte("two\nno newline at end"))
+	w.Close()
+
+	want := "line one\nline two\nno newline at end"
+	if got := out.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFilteringWriterDropsUnterminatedMatchingLine(t *testing.T) {
+	var out bytes.Buffer
+	w := newFilteringWriter(&out, regexp.MustCompile("^noisy"))
+
+	w.Write([]byte("noisy partial"))
+	w.Close()
+
+	if got := out.String(); got != "" {
+		t.Errorf("expected the unterminated matching line to be dropped, got %q", got)
+	}
+}
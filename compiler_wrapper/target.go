@@ -0,0 +1,38 @@
+package main
+
+// builderTarget returns the canonical target triple for cfg, as derived
+// from its configuration. It's the one place other steps go to ask "what
+// target is this?" rather than each re-deriving it their own way.
+func builderTarget(cfg *config) string {
+	return cfg.Target
+}
+
+// exportTargetEnabled reports whether COMPILER_WRAPPER_EXPORT_TARGET=1 is
+// set, opting into exposing the wrapper's computed target to the compiler
+// and the tools it invokes.
+func exportTargetEnabled(e env) bool {
+	v, ok := e.getenv("COMPILER_WRAPPER_EXPORT_TARGET")
+	return ok && v == "1"
+}
+
+// processExportTarget injects CROSTC_TARGET=<target> into compilerCmd's
+// EnvUpdates when exportTargetEnabled(e), so downstream tools invoked by
+// the compiler (some wrapper scripts, some build-time codegen) can learn
+// the canonical target without re-deriving it themselves.
+func processExportTarget(e env, cfg *config, compilerCmd *command) *command {
+	if !exportTargetEnabled(e) {
+		return compilerCmd
+	}
+	target := builderTarget(cfg)
+	if target == "" {
+		return compilerCmd
+	}
+
+	newCmd := *compilerCmd
+	newCmd.EnvUpdates = make(map[string]string, len(compilerCmd.EnvUpdates)+1)
+	for k, v := range compilerCmd.EnvUpdates {
+		newCmd.EnvUpdates[k] = v
+	}
+	newCmd.EnvUpdates["CROSTC_TARGET"] = target
+	return &newCmd
+}
@@ -0,0 +1,43 @@
+package main
+
+import "testing"
+
+func TestProcessExportTargetInjectsEnvUpdate(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnueabihf"}
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_EXPORT_TARGET": "1"})
+
+	got := processExportTarget(e, cfg, &command{})
+	if got.EnvUpdates["CROSTC_TARGET"] != builderTarget(cfg) {
+		t.Errorf("got %v", got.EnvUpdates)
+	}
+}
+
+func TestProcessExportTargetDisabledByDefault(t *testing.T) {
+	cfg := &config{Target: "armv7a-cros-linux-gnueabihf"}
+	cmd := &command{}
+	got := processExportTarget(newFakeEnv(map[string]string{}), cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected no-op without COMPILER_WRAPPER_EXPORT_TARGET, got %v", got.EnvUpdates)
+	}
+}
+
+func TestProcessExportTargetNoopWithoutTarget(t *testing.T) {
+	cfg := &config{}
+	cmd := &command{}
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_EXPORT_TARGET": "1"})
+	got := processExportTarget(e, cfg, cmd)
+	if got != cmd {
+		t.Errorf("expected no-op when the config has no target, got %v", got.EnvUpdates)
+	}
+}
+
+func TestProcessExportTargetPreservesExistingEnvUpdates(t *testing.T) {
+	cfg := &config{Target: "x86_64-cros-linux-gnu"}
+	e := newFakeEnv(map[string]string{"COMPILER_WRAPPER_EXPORT_TARGET": "1"})
+	cmd := &command{EnvUpdates: map[string]string{"OTHER": "1"}}
+
+	got := processExportTarget(e, cfg, cmd)
+	if got.EnvUpdates["OTHER"] != "1" {
+		t.Errorf("expected existing EnvUpdates to survive, got %v", got.EnvUpdates)
+	}
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// tidyExcludeRegexEnvVar names source paths that shouldn't be run through
+// clang-tidy, for generated sources (e.g. under out/) where tidy warnings
+// are noise the user can't act on anyway. The file is still compiled
+// normally either way.
+const tidyExcludeRegexEnvVar = "TIDY_EXCLUDE_REGEX"
+
+// sourceFileArgs returns the non-flag arguments of compilerCmd.Args, which
+// for a compile invocation are the input source files.
+func sourceFileArgs(compilerCmd *command) []string {
+	var sources []string
+	for _, arg := range compilerCmd.Args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		sources = append(sources, arg)
+	}
+	return sources
+}
+
+// tidyExcluded reports whether sourceFile matches the TIDY_EXCLUDE_REGEX
+// pattern, if one is configured. An invalid pattern is treated as no
+// exclusion, since failing to compile over a tidy misconfiguration would be
+// far worse than just always running tidy.
+func tidyExcluded(e env, sourceFile string) bool {
+	pattern, ok := e.getenv(tidyExcludeRegexEnvVar)
+	if !ok || pattern == "" {
+		return false
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false
+	}
+	return re.MatchString(sourceFile)
+}
+
+// shouldRunClangTidy reports whether compilerCmd's source files should be
+// run through clang-tidy as a sub-invocation alongside the real compile.
+// It's false when there's no source file to tidy (e.g. a link-only
+// invocation) or when every source file is excluded via
+// TIDY_EXCLUDE_REGEX.
+func shouldRunClangTidy(e env, compilerCmd *command) bool {
+	sources := sourceFileArgs(compilerCmd)
+	if len(sources) == 0 {
+		return false
+	}
+	for _, src := range sources {
+		if !tidyExcluded(e, src) {
+			return true
+		}
+	}
+	return false
+}
+
+// tidyCompileDBDirEnvVar, when set, points clang-tidy at the directory
+// holding a compile_commands.json so it resolves each source file's flags
+// from the database instead of needing them forwarded manually.
+const tidyCompileDBDirEnvVar = "TIDY_COMPILE_DB_DIR"
+
+// clangTidyArgs builds the argument list for the clang-tidy sub-invocation
+// of compilerCmd. If TIDY_COMPILE_DB_DIR is set, clang-tidy is pointed at
+// that compile database with -p and gets its flags from there, so the
+// original compiler arguments aren't forwarded; otherwise they're forwarded
+// after "--" so clang-tidy's own driver can resolve the same flags (include
+// paths, defines, standard version, etc.) the real compile used.
+func clangTidyArgs(e env, compilerCmd *command) []string {
+	args := append([]string{}, sourceFileArgs(compilerCmd)...)
+	if dbDir, ok := e.getenv(tidyCompileDBDirEnvVar); ok && dbDir != "" {
+		return append(args, "-p", dbDir)
+	}
+	args = append(args, "--")
+	return append(args, compilerCmd.Args...)
+}
+
+// runClangTidy runs clang-tidy over compilerCmd's source files.
+func runClangTidy(e env, tidyPath string, compilerCmd *command) error {
+	return exec.Command(tidyPath, clangTidyArgs(e, compilerCmd)...).Run()
+}
+
+// tidyPathEnvVar names the clang-tidy binary to run alongside the real
+// compile. Tidy support is a no-op without it: there's no sane default
+// clang-tidy to fall back on the way there is for, say, the old wrapper's
+// python interpreter.
+const tidyPathEnvVar = "TIDY_PATH"
+
+// maybeRunClangTidy runs clang-tidy over compilerCmd's source files as a
+// side invocation alongside the real compile, when TIDY_PATH is set and
+// shouldRunClangTidy agrees there's something to tidy. A clang-tidy
+// failure (a lint finding, or tidy itself erroring) is reported to stderr
+// but never fails the build: tidy is a supplementary check, not a gate.
+func maybeRunClangTidy(e env, compilerCmd *command) {
+	tidyPath, ok := e.getenv(tidyPathEnvVar)
+	if !ok || tidyPath == "" || !shouldRunClangTidy(e, compilerCmd) {
+		return
+	}
+	if err := runClangTidy(e, tidyPath, compilerCmd); err != nil {
+		fmt.Fprintf(e.stderr(), "clang-tidy: %v\n", err)
+	}
+}
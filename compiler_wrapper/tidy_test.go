@@ -0,0 +1,97 @@
+package main
+
+import "testing"
+
+func TestTidyExcludedMatchesPattern(t *testing.T) {
+	e := newFakeEnv(map[string]string{tidyExcludeRegexEnvVar: `^out/`})
+	if !tidyExcluded(e, "out/gen/foo.cc") {
+		t.Error("expected out/gen/foo.cc to be excluded")
+	}
+	if tidyExcluded(e, "src/foo.cc") {
+		t.Error("expected src/foo.cc not to be excluded")
+	}
+}
+
+func TestTidyExcludedNoopWithoutEnvVar(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if tidyExcluded(e, "out/gen/foo.cc") {
+		t.Error("expected no exclusion when TIDY_EXCLUDE_REGEX is unset")
+	}
+}
+
+func TestTidyExcludedInvalidPatternDoesntExclude(t *testing.T) {
+	e := newFakeEnv(map[string]string{tidyExcludeRegexEnvVar: `(`})
+	if tidyExcluded(e, "out/gen/foo.cc") {
+		t.Error("expected an invalid pattern to never exclude")
+	}
+}
+
+func TestShouldRunClangTidyExcludedPathSkipsTidy(t *testing.T) {
+	e := newFakeEnv(map[string]string{tidyExcludeRegexEnvVar: `^out/`})
+	cmd := &command{Args: []string{"-c", "out/gen/foo.cc"}}
+	if shouldRunClangTidy(e, cmd) {
+		t.Error("expected an excluded path to skip tidy")
+	}
+}
+
+func TestShouldRunClangTidyNormalPathRunsTidy(t *testing.T) {
+	e := newFakeEnv(map[string]string{tidyExcludeRegexEnvVar: `^out/`})
+	cmd := &command{Args: []string{"-c", "src/foo.cc"}}
+	if !shouldRunClangTidy(e, cmd) {
+		t.Error("expected a non-excluded path to run tidy")
+	}
+}
+
+func TestShouldRunClangTidyNoSourceFiles(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-v"}}
+	if shouldRunClangTidy(e, cmd) {
+		t.Error("expected no tidy run when there are no source files")
+	}
+}
+
+func TestClangTidyArgsUsesCompileDBWhenSet(t *testing.T) {
+	e := newFakeEnv(map[string]string{tidyCompileDBDirEnvVar: "/build/out"})
+	cmd := &command{Args: []string{"-c", "-Wall", "foo.cc"}}
+
+	got := clangTidyArgs(e, cmd)
+	want := []string{"foo.cc", "-p", "/build/out"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestClangTidyArgsForwardsCompilerArgsWithoutCompileDB(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	cmd := &command{Args: []string{"-c", "-Wall", "foo.cc"}}
+
+	got := clangTidyArgs(e, cmd)
+	want := []string{"foo.cc", "--", "-c", "-Wall", "foo.cc"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestSourceFileArgsSkipsFlags(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wall", "foo.cc", "-o", "foo.o"}}
+	got := sourceFileArgs(cmd)
+	want := []string{"foo.cc", "foo.o"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTTY reports whether w refers to a terminal, so features like progress
+// output or color diagnostics can decide whether to use them. Only an
+// *os.File backed by a real character device can be a terminal; anything
+// else (a bytes.Buffer, a non-pty pipe, ...) reports false. Implemented
+// directly against the TCGETS ioctl so the wrapper doesn't need an extra
+// module dependency for something this small.
+func isTTY(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	var termios syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), syscall.TCGETS, uintptr(unsafe.Pointer(&termios)))
+	return errno == 0
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestIsTTYFalseForBuffer(t *testing.T) {
+	var buf bytes.Buffer
+	if isTTY(&buf) {
+		t.Error("expected a bytes.Buffer to never be reported as a TTY")
+	}
+}
+
+// TestIsTTYFalseForPipe exercises the *os.File path with a plain pipe,
+// which is a character-ish stream but not a terminal. A real terminal (e.g.
+// a pty opened via /dev/ptmx, or the process's own stdin when run
+// interactively) would make isTTY return true; that's not exercised here
+// since tests don't run attached to one.
+func TestIsTTYFalseForPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	if isTTY(w) {
+		t.Error("expected a pipe to not be reported as a TTY")
+	}
+}
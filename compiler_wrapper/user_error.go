@@ -0,0 +1,22 @@
+package main
+
+import "fmt"
+
+// userError indicates the invocation itself is wrong (as opposed to an
+// internal wrapper bug), so callers can report it without a stack trace or
+// "please file a bug" framing.
+type userError struct {
+	msg string
+}
+
+func (e *userError) Error() string { return e.msg }
+
+func newUserError(format string, args ...interface{}) error {
+	return &userError{msg: fmt.Sprintf(format, args...)}
+}
+
+// isUserError reports whether err (or one it wraps) is a userError.
+func isUserError(err error) bool {
+	_, ok := err.(*userError)
+	return ok
+}
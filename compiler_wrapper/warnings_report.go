@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// maxReportedWarnings caps how many individual warning lines go into a
+// report, so a build that emits thousands of warnings doesn't produce a
+// multi-megabyte artifact; the report's count field still reflects the true
+// total.
+const maxReportedWarnings = 20
+
+// logAllWarningsEnabled reports whether CROSTC_LOG_ALL_WARNINGS=1 is set,
+// requesting a warnings report even for compiles that otherwise succeeded.
+func logAllWarningsEnabled(e env) bool {
+	v, ok := e.getenv("CROSTC_LOG_ALL_WARNINGS")
+	return ok && v == "1"
+}
+
+// scanWarningLines returns every line of stderrText that looks like a
+// compiler warning.
+func scanWarningLines(stderrText string) []string {
+	var warnings []string
+	for _, line := range strings.Split(stderrText, "\n") {
+		if strings.Contains(line, "warning:") {
+			warnings = append(warnings, line)
+		}
+	}
+	return warnings
+}
+
+// renderWarningsReport formats a lightweight report: the total warning
+// count, followed by up to maxReportedWarnings of the warning lines
+// themselves.
+func renderWarningsReport(warnings []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "warnings: %d\n", len(warnings))
+	shown := warnings
+	if len(shown) > maxReportedWarnings {
+		shown = shown[:maxReportedWarnings]
+	}
+	for _, w := range shown {
+		b.WriteString(w)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// maybeReportAllWarnings scans stderrText for compiler warnings and, when
+// CROSTC_LOG_ALL_WARNINGS is enabled and any were found, writes a report
+// file into the resolved artifacts dir (see compilerArtifactsDir) named
+// after output. It's meant for a compile that otherwise succeeded, where a
+// -Werror-fatal build would never have reached this point with those
+// warnings still present.
+func maybeReportAllWarnings(e env, cfg *config, output, stderrText string) error {
+	artifactsDir, _ := compilerArtifactsDir(e, cfg)
+	if !logAllWarningsEnabled(e) || artifactsDir == "" {
+		return nil
+	}
+	warnings := scanWarningLines(stderrText)
+	if len(warnings) == 0 {
+		return nil
+	}
+
+	name, err := reportName(e, artifactsDir, output)
+	if err != nil {
+		return err
+	}
+	reportPath := artifactsDir + "/" + name + ".warnings.txt"
+	report := truncateCapturedOutput(e, renderWarningsReport(warnings))
+	return os.WriteFile(reportPath, []byte(report), 0o644)
+}
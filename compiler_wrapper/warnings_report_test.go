@@ -0,0 +1,96 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogAllWarningsEnabled(t *testing.T) {
+	if logAllWarningsEnabled(newFakeEnv(map[string]string{})) {
+		t.Error("expected warnings logging to be off by default")
+	}
+	if !logAllWarningsEnabled(newFakeEnv(map[string]string{"CROSTC_LOG_ALL_WARNINGS": "1"})) {
+		t.Error("expected warnings logging to be on when the var is \"1\"")
+	}
+}
+
+func TestScanWarningLines(t *testing.T) {
+	stderrText := "foo.c:1:1: warning: unused variable 'x'\nfoo.c:2:1: note: see above\nfoo.c:3:1: warning: implicit conversion"
+	got := scanWarningLines(stderrText)
+	if len(got) != 2 {
+		t.Fatalf("got %d warnings, want 2: %v", len(got), got)
+	}
+}
+
+func TestMaybeReportAllWarningsWritesReport(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config{ArtifactsDir: dir}
+	e := newFakeEnv(map[string]string{"CROSTC_LOG_ALL_WARNINGS": "1"})
+	stderrText := "foo.c:1:1: warning: unused variable 'x'\nfoo.c:2:1: warning: implicit conversion"
+
+	if err := maybeReportAllWarnings(e, cfg, "foo.o", stderrText); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d files in artifacts dir, want 1", len(entries))
+	}
+	contents, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(contents), "warnings: 2\n") {
+		t.Errorf("report doesn't start with expected count, got %q", contents)
+	}
+}
+
+func TestMaybeReportAllWarningsNoopWhenDisabled(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config{ArtifactsDir: dir}
+	e := newFakeEnv(map[string]string{})
+
+	if err := maybeReportAllWarnings(e, cfg, "foo.o", "warning: x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no report file when disabled, got %v", entries)
+	}
+}
+
+func TestMaybeReportAllWarningsNoopWithoutArtifactsDir(t *testing.T) {
+	cfg := &config{}
+	e := newFakeEnv(map[string]string{"CROSTC_LOG_ALL_WARNINGS": "1"})
+
+	if err := maybeReportAllWarnings(e, cfg, "foo.o", "warning: x"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestMaybeReportAllWarningsNoopWithoutWarnings(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config{ArtifactsDir: dir}
+	e := newFakeEnv(map[string]string{"CROSTC_LOG_ALL_WARNINGS": "1"})
+
+	if err := maybeReportAllWarnings(e, cfg, "foo.o", "no warnings here"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("expected no report file without warnings, got %v", entries)
+	}
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// matchingConfTestArg returns the argument that marks compilerCmd as an
+// autoconf-style configure-test compile, or "" if none matched. Configure
+// tests are typically `conftest.c` sources compiled with output discarded to
+// `/dev/null`.
+func matchingConfTestArg(compilerCmd *command) string {
+	for _, arg := range compilerCmd.Args {
+		if arg == "/dev/null" || strings.Contains(arg, "conftest.c") {
+			return arg
+		}
+	}
+	return ""
+}
+
+// isLikelyAConfTest reports whether compilerCmd looks like a configure-script
+// probe compile rather than a real build step.
+func isLikelyAConfTest(compilerCmd *command) bool {
+	return matchingConfTestArg(compilerCmd) != ""
+}
+
+// useDoubleBuildForWarnings decides whether the wrapper should re-run the
+// compile with relaxed -Werror handling in order to capture warnings that a
+// -Werror-fatal first build would otherwise swallow. Configure-test compiles
+// are excluded: they run very often and don't need accurate warnings, so
+// doubling their cost isn't worth it.
+func useDoubleBuildForWarnings(e env, compilerCmd *command) bool {
+	if arg := matchingConfTestArg(compilerCmd); arg != "" {
+		logDebugf(e, "skipping double-build for likely conf-test (matched %q)", arg)
+		return false
+	}
+	return true
+}
+
+// logDebugf writes a diagnostic line to the wrapper's stderr when debug
+// logging has been enabled via CROSTC_DEBUG. It is meant for humans triaging
+// a specific build, not for machine-readable output.
+func logDebugf(e env, format string, args ...interface{}) {
+	if _, ok := e.getenv("CROSTC_DEBUG"); !ok {
+		return
+	}
+	fmt.Fprintf(e.stderr(), "crostc debug: "+format+"\n", args...)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsLikelyAConfTest(t *testing.T) {
+	cases := []struct {
+		name string
+		args []string
+		want bool
+	}{
+		{"conftest source", []string{"-c", "conftest.c", "-o", "conftest.o"}, true},
+		{"dev null output", []string{"-c", "foo.c", "-o", "/dev/null"}, true},
+		{"normal compile", []string{"-c", "foo.c", "-o", "foo.o"}, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := isLikelyAConfTest(&command{Path: "clang", Args: tc.args})
+			if got != tc.want {
+				t.Errorf("isLikelyAConfTest(%v) = %v, want %v", tc.args, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUseDoubleBuildForWarningsLogsMatchedArgUnderDebug(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	if useDoubleBuildForWarnings(e, &command{Args: []string{"-c", "conftest.c"}}) {
+		t.Fatal("expected double-build to be skipped for a conf-test compile")
+	}
+	if !strings.Contains(e.stderrBuf.String(), "conftest.c") {
+		t.Errorf("expected debug log to mention the matched arg, got: %q", e.stderrBuf.String())
+	}
+}
+
+func TestUseDoubleBuildForWarningsNoLogWithoutDebug(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	if !useDoubleBuildForWarnings(e, &command{Args: []string{"-c", "foo.c"}}) {
+		t.Fatal("expected double-build to proceed for a normal compile")
+	}
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no debug output, got: %q", e.stderrBuf.String())
+	}
+}
+
+func TestUseDoubleBuildForWarningsMatchesDevNull(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	if useDoubleBuildForWarnings(e, &command{Args: []string{"-c", "foo.c", "-o", "/dev/null"}}) {
+		t.Fatal("expected double-build to be skipped for a /dev/null output compile")
+	}
+	if !strings.Contains(e.stderrBuf.String(), "/dev/null") {
+		t.Errorf("expected debug log to mention the matched arg, got: %q", e.stderrBuf.String())
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import "regexp"
+
+// werrorFlagPattern matches the diagnostic-group bracket clang prints for an
+// error, e.g. "[-Werror,-Wfoo]" for a warning promoted to an error by
+// -Werror, or the bare "[-Wfoo]" for a warning clang itself turns into an
+// error by default (no "-Werror," prefix), such as
+// -Wimplicit-function-declaration.
+var werrorFlagPattern = regexp.MustCompile(`\[-W(?:error,-W)?([\w-]+)\]`)
+
+// getWnoErrorFlags scans compiler stderr for warnings that were escalated to
+// hard errors and returns the -Wno-error=<name> flags needed to downgrade
+// each one back to a warning for a retry build.
+func getWnoErrorFlags(stderr string) []string {
+	seen := map[string]bool{}
+	var flags []string
+	for _, m := range werrorFlagPattern.FindAllStringSubmatch(stderr, -1) {
+		name := m[1]
+		// A bare "-Werror" bracket (no specific group) isn't a nameable
+		// warning to disable.
+		if name == "error" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		flags = append(flags, "-Wno-error="+name)
+	}
+	return flags
+}
+
+// disableWerrorFlags returns a copy of compilerCmd with -Wno-error=<name>
+// flags for every warning-turned-error found in stderr, plus a final broad
+// -Wno-error, appended to retry the build with Werror relaxed. Flags already
+// present on the command (injected earlier, or user-supplied) aren't
+// duplicated, and the existing argument order is preserved.
+func disableWerrorFlags(compilerCmd *command, stderr string) *command {
+	extra := append(getWnoErrorFlags(stderr), "-Wno-error")
+
+	newArgs := dedupeFlags(append(append([]string{}, compilerCmd.Args...), extra...))
+
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
+
+// dedupeFlags returns args with duplicate entries removed, keeping only the
+// first occurrence of each and preserving relative order.
+func dedupeFlags(args []string) []string {
+	seen := make(map[string]bool, len(args))
+	out := make([]string, 0, len(args))
+	for _, a := range args {
+		if seen[a] {
+			continue
+		}
+		seen[a] = true
+		out = append(out, a)
+	}
+	return out
+}
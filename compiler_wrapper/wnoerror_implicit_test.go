@@ -0,0 +1,27 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestGetWnoErrorFlagsRealClangImplicitDiagnostics locks in -Wno-error=<name>
+// synthesis against full clang diagnostic output (including source context
+// and caret lines), since -Wimplicit-function-declaration and -Wimplicit-int
+// are errors by default and so appear without a "-Werror," prefix in their
+// brackets.
+func TestGetWnoErrorFlagsRealClangImplicitDiagnostics(t *testing.T) {
+	stderr := `foo.c:3:5: error: call to undeclared function 'bar'; ISO C99 and later do not support implicit function declarations [-Wimplicit-function-declaration]
+    3 |     bar();
+      |     ^
+foo.c:4:1: error: type specifier missing, defaults to 'int' [-Wimplicit-int]
+    4 | baz() {}
+      | ^
+2 errors generated.
+`
+	got := getWnoErrorFlags(stderr)
+	want := []string{"-Wno-error=implicit-function-declaration", "-Wno-error=implicit-int"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("getWnoErrorFlags(...) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "strings"
+
+// workingDirectoryFlag and workingDirectoryFlagPrefix are clang's two forms
+// of -working-directory, which changes how it resolves relative paths
+// (independent of the process's actual cwd).
+const (
+	workingDirectoryFlag       = "-working-directory"
+	workingDirectoryFlagPrefix = "-working-directory="
+)
+
+// userWorkingDirectory returns the directory a user-supplied -working-directory
+// (or -working-directory=<dir>) names, if compilerCmd carries one.
+func userWorkingDirectory(compilerCmd *command) (dir string, ok bool) {
+	for i, arg := range compilerCmd.Args {
+		switch {
+		case arg == workingDirectoryFlag:
+			if i+1 < len(compilerCmd.Args) {
+				return compilerCmd.Args[i+1], true
+			}
+		case strings.HasPrefix(arg, workingDirectoryFlagPrefix):
+			return strings.TrimPrefix(arg, workingDirectoryFlagPrefix), true
+		}
+	}
+	return "", false
+}
+
+// effectiveWorkingDir returns the directory that cwd-relative transforms
+// (clang dir relativization, -B injection) should resolve against: the
+// user's -working-directory if they passed one, otherwise defaultCwd (the
+// process's actual working directory).
+func effectiveWorkingDir(defaultCwd string, compilerCmd *command) string {
+	if dir, ok := userWorkingDirectory(compilerCmd); ok {
+		return dir
+	}
+	return defaultCwd
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUserWorkingDirectorySeparatedForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-working-directory", "/src/out", "foo.c"}}
+	dir, ok := userWorkingDirectory(cmd)
+	if !ok || dir != "/src/out" {
+		t.Errorf("got (%q, %v), want (/src/out, true)", dir, ok)
+	}
+}
+
+func TestUserWorkingDirectoryCombinedForm(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-working-directory=/src/out", "foo.c"}}
+	dir, ok := userWorkingDirectory(cmd)
+	if !ok || dir != "/src/out" {
+		t.Errorf("got (%q, %v), want (/src/out, true)", dir, ok)
+	}
+}
+
+func TestUserWorkingDirectoryAbsent(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "foo.c"}}
+	if _, ok := userWorkingDirectory(cmd); ok {
+		t.Error("expected no working directory to be found")
+	}
+}
+
+func TestEffectiveWorkingDirPrefersUserFlag(t *testing.T) {
+	cmd := &command{Args: []string{"-working-directory=/src/out", "foo.c"}}
+	if got := effectiveWorkingDir("/proc/cwd", cmd); got != "/src/out" {
+		t.Errorf("got %q, want /src/out", got)
+	}
+}
+
+func TestEffectiveWorkingDirFallsBackToDefault(t *testing.T) {
+	cmd := &command{Args: []string{"foo.c"}}
+	if got := effectiveWorkingDir("/proc/cwd", cmd); got != "/proc/cwd" {
+		t.Errorf("got %q, want /proc/cwd", got)
+	}
+}
+
+func TestMaybeSaveInvocationUsesWorkingDirectoryFlag(t *testing.T) {
+	dir := t.TempDir()
+	e := newFakeEnv(map[string]string{saveInvocationDirEnvVar: dir})
+	cmd := &command{Path: "/usr/bin/gcc", Args: []string{"-working-directory=/src/out", "-c", "foo.c"}}
+
+	maybeSaveInvocation(e, "/proc/cwd", "foo.o", cmd)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("got %d files, want 1", len(entries))
+	}
+	contents, err := os.ReadFile(filepath.Join(dir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(contents), "cd '/src/out'") {
+		t.Errorf("expected script to cd into the -working-directory target, got:\n%s", contents)
+	}
+}
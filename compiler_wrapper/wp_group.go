@@ -0,0 +1,70 @@
+package main
+
+import "strings"
+
+// wpGroupPrefixes are the two gcc/clang spellings for forwarding a
+// comma-separated group of options straight to the preprocessor, as a
+// single command-line argument.
+var wpGroupPrefixes = []string{"-Wp,", "-Xpreprocessor,"}
+
+// splitWpGroup splits a "-Wp,opt1,opt2" (or "-Xpreprocessor,...") argument
+// into its prefix and the individual inner options, so each one can be
+// inspected/translated on its own. ok is false if arg isn't one of these
+// group forms.
+func splitWpGroup(arg string) (prefix string, inner []string, ok bool) {
+	for _, p := range wpGroupPrefixes {
+		if rest, found := strings.CutPrefix(arg, p); found {
+			return p, strings.Split(rest, ","), true
+		}
+	}
+	return "", nil, false
+}
+
+// joinWpGroup recombines prefix and inner back into a single "-Wp,..."-style
+// argument.
+func joinWpGroup(prefix string, inner []string) string {
+	return prefix + strings.Join(inner, ",")
+}
+
+// filterWpGroupGCCOnlyMFlags drops gcc-only -m flags (see
+// gccOnlyMFlagPrefixes) found inside -Wp,/-Xpreprocessor, groups when
+// building with clang, the same way filterGCCOnlyMFlags does for standalone
+// -m flags; clang rejects these outright even when nested inside a
+// forwarded group. It's a no-op for gcc and for arguments that aren't a
+// -Wp,/-Xpreprocessor, group, or that contain no such inner flag.
+func filterWpGroupGCCOnlyMFlags(compilerIsClang bool, compilerCmd *command) *command {
+	if !compilerIsClang {
+		return compilerCmd
+	}
+
+	changed := false
+	newArgs := make([]string, 0, len(compilerCmd.Args))
+	for _, arg := range compilerCmd.Args {
+		prefix, inner, ok := splitWpGroup(arg)
+		if !ok {
+			newArgs = append(newArgs, arg)
+			continue
+		}
+
+		keep := make([]string, 0, len(inner))
+		for _, opt := range inner {
+			if isGCCOnlyMFlag(opt) {
+				changed = true
+				continue
+			}
+			keep = append(keep, opt)
+		}
+		if len(keep) == 0 {
+			changed = true
+			continue
+		}
+		newArgs = append(newArgs, joinWpGroup(prefix, keep))
+	}
+
+	if !changed {
+		return compilerCmd
+	}
+	newCmd := *compilerCmd
+	newCmd.Args = newArgs
+	return &newCmd
+}
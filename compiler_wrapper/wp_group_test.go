@@ -0,0 +1,74 @@
+package main
+
+import "testing"
+
+func TestSplitWpGroup(t *testing.T) {
+	prefix, inner, ok := splitWpGroup("-Wp,-DFOO,-DBAR")
+	if !ok {
+		t.Fatal("expected a -Wp, group to split")
+	}
+	if prefix != "-Wp," {
+		t.Errorf("prefix = %q, want -Wp,", prefix)
+	}
+	want := []string{"-DFOO", "-DBAR"}
+	if len(inner) != len(want) {
+		t.Fatalf("got %v, want %v", inner, want)
+	}
+	for i := range want {
+		if inner[i] != want[i] {
+			t.Errorf("inner %d: got %q, want %q", i, inner[i], want[i])
+		}
+	}
+}
+
+func TestSplitWpGroupNotAGroup(t *testing.T) {
+	if _, _, ok := splitWpGroup("-DFOO"); ok {
+		t.Error("expected a plain flag not to be treated as a group")
+	}
+}
+
+func TestFilterWpGroupGCCOnlyMFlagsDropsInnerFlag(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wp,-mfpmath=sse,-MD", "foo.c"}}
+	got := filterWpGroupGCCOnlyMFlags(true, cmd)
+
+	want := []string{"-c", "-Wp,-MD", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestFilterWpGroupGCCOnlyMFlagsDropsWholeGroupWhenEmptied(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wp,-mfpmath=sse", "foo.c"}}
+	got := filterWpGroupGCCOnlyMFlags(true, cmd)
+
+	want := []string{"-c", "foo.c"}
+	if len(got.Args) != len(want) {
+		t.Fatalf("got %v, want %v", got.Args, want)
+	}
+	for i := range want {
+		if got.Args[i] != want[i] {
+			t.Errorf("arg %d: got %q, want %q", i, got.Args[i], want[i])
+		}
+	}
+}
+
+func TestFilterWpGroupGCCOnlyMFlagsNoopForGCC(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wp,-mfpmath=sse", "foo.c"}}
+	got := filterWpGroupGCCOnlyMFlags(false, cmd)
+	if got != cmd {
+		t.Error("expected gcc invocations to be left untouched")
+	}
+}
+
+func TestFilterWpGroupGCCOnlyMFlagsNoopWithoutGCCOnlyInner(t *testing.T) {
+	cmd := &command{Args: []string{"-c", "-Wp,-DFOO,-DBAR", "foo.c"}}
+	got := filterWpGroupGCCOnlyMFlags(true, cmd)
+	if got != cmd {
+		t.Error("expected a group with no gcc-only inner flags to be left untouched")
+	}
+}
@@ -0,0 +1,38 @@
+package main
+
+import "strings"
+
+// wrapperLikeTokens are substrings that, if found in a parent process's
+// cmdline, suggest it's itself a compiler wrapper (this one, distcc's, or
+// similar), meaning two wrappers may have been stacked unintentionally.
+var wrapperLikeTokens = []string{"distcc", "ccache", "compiler_wrapper", "gomacc"}
+
+// looksLikeCompilerWrapper reports whether cmdline looks like it's invoking
+// some other compiler-wrapping layer, by a cheap substring heuristic. This
+// is advisory only: false positives (a path that happens to contain one of
+// these words) are acceptable since the result is just a debug warning.
+func looksLikeCompilerWrapper(cmdline string) bool {
+	lower := strings.ToLower(cmdline)
+	for _, tok := range wrapperLikeTokens {
+		if strings.Contains(lower, tok) {
+			return true
+		}
+	}
+	return false
+}
+
+// warnIfChainedWrapper inspects the immediate parent process (the first
+// entry of chain, as from collectAllParentProcesses) and logs a debug
+// warning if it looks like another compiler wrapper, since two wrappers
+// stacked on top of each other slow builds dramatically without any
+// functional benefit. It's advisory only -- it never blocks or alters the
+// compile.
+func warnIfChainedWrapper(e env, chain []parentProcessInfo) {
+	if len(chain) == 0 {
+		return
+	}
+	parent := chain[0]
+	if looksLikeCompilerWrapper(parent.Cmdline) {
+		logDebugf(e, "parent process (pid %d, %s) looks like another compiler wrapper; wrappers may be stacked, which can slow builds significantly", parent.PID, parent.Cmdline)
+	}
+}
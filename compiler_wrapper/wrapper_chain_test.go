@@ -0,0 +1,49 @@
+package main
+
+import "testing"
+
+func TestLooksLikeCompilerWrapper(t *testing.T) {
+	cases := []struct {
+		cmdline string
+		want    bool
+	}{
+		{"/usr/bin/distcc gcc -c foo.c", true},
+		{"/usr/bin/ccache clang -c foo.c", true},
+		{"/usr/bin/gcc -c foo.c", false},
+	}
+	for _, tc := range cases {
+		if got := looksLikeCompilerWrapper(tc.cmdline); got != tc.want {
+			t.Errorf("looksLikeCompilerWrapper(%q) = %v, want %v", tc.cmdline, got, tc.want)
+		}
+	}
+}
+
+func TestWarnIfChainedWrapperLogsUnderDebug(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	chain := []parentProcessInfo{{PID: 42, Comm: "distcc", Cmdline: "/usr/bin/distcc gcc -c foo.c"}}
+
+	warnIfChainedWrapper(e, chain)
+
+	if got := e.stderrBuf.String(); got == "" {
+		t.Error("expected a debug warning about the wrapper-like parent")
+	}
+}
+
+func TestWarnIfChainedWrapperSilentForOrdinaryParent(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	chain := []parentProcessInfo{{PID: 42, Comm: "bash", Cmdline: "/bin/bash build.sh"}}
+
+	warnIfChainedWrapper(e, chain)
+
+	if got := e.stderrBuf.String(); got != "" {
+		t.Errorf("expected no warning for an ordinary parent, got %q", got)
+	}
+}
+
+func TestWarnIfChainedWrapperNoopWithEmptyChain(t *testing.T) {
+	e := newFakeEnv(map[string]string{"CROSTC_DEBUG": "1"})
+	warnIfChainedWrapper(e, nil)
+	if got := e.stderrBuf.String(); got != "" {
+		t.Errorf("expected no warning with an empty chain, got %q", got)
+	}
+}
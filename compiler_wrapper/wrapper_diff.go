@@ -0,0 +1,120 @@
+// compareCommands, renderWrapperDifferences, and
+// maybeEmitWrapperDifferencesJSON are deliberately not wired into
+// callCompiler yet. Comparing the old wrapper against this one needs the
+// old wrapper's own *computed* compiler invocation to diff against
+// finalCmd, not just a command that runs the old wrapper (see
+// buildOldWrapperCommand in old_wrapper_exec.go) -- and getting that back
+// out means either running the old wrapper in some dry-run mode it
+// doesn't have, or actually running it and then somehow inferring what it
+// executed after the fact. Neither exists, and inventing an ad hoc
+// protocol with the old (python, unmaintained) wrapper isn't something to
+// improvise here. maybeWarnOldWrapperPath (old_wrapper_path.go) stays as
+// the one live signal that cfg.OldWrapperPath is set; the comparison this
+// cluster of requests assumed needs its own design pass against the old
+// wrapper's actual interface before it can be wired in.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// compareWrapperJSONEnvVar opts into emitting compareCommands' structured
+// differences as JSON to stderr, for migration dashboards that want to
+// ingest them directly instead of parsing the concatenated string form.
+const compareWrapperJSONEnvVar = "COMPARE_WRAPPER_JSON"
+
+// wrapperDifferenceKind names what aspect of two commands differs.
+type wrapperDifferenceKind string
+
+const (
+	wrapperDifferencePath wrapperDifferenceKind = "path"
+	wrapperDifferenceArg  wrapperDifferenceKind = "arg"
+	wrapperDifferenceEnv  wrapperDifferenceKind = "env"
+)
+
+// wrapperDifference is one concrete difference found between an old and a
+// new compiler invocation for the same input.
+type wrapperDifference struct {
+	Index int                   `json:"index"`
+	Kind  wrapperDifferenceKind `json:"kind"`
+	Old   string                `json:"old"`
+	New   string                `json:"new"`
+}
+
+// compareCommands compares oldCmd and newCmd (typically the old wrapper's
+// computed invocation against this wrapper's) and returns every
+// difference found: a changed path, a changed or added/removed argument at
+// a given index, and any EnvUpdates key whose value differs (keyed by name
+// rather than index, since map iteration order isn't meaningful).
+func compareCommands(oldCmd, newCmd *command) []wrapperDifference {
+	var diffs []wrapperDifference
+
+	if oldCmd.Path != newCmd.Path {
+		diffs = append(diffs, wrapperDifference{Kind: wrapperDifferencePath, Old: oldCmd.Path, New: newCmd.Path})
+	}
+
+	maxArgs := len(oldCmd.Args)
+	if len(newCmd.Args) > maxArgs {
+		maxArgs = len(newCmd.Args)
+	}
+	for i := 0; i < maxArgs; i++ {
+		var oldArg, newArg string
+		if i < len(oldCmd.Args) {
+			oldArg = oldCmd.Args[i]
+		}
+		if i < len(newCmd.Args) {
+			newArg = newCmd.Args[i]
+		}
+		if oldArg != newArg {
+			diffs = append(diffs, wrapperDifference{Index: i, Kind: wrapperDifferenceArg, Old: oldArg, New: newArg})
+		}
+	}
+
+	keys := map[string]bool{}
+	for k := range oldCmd.EnvUpdates {
+		keys[k] = true
+	}
+	for k := range newCmd.EnvUpdates {
+		keys[k] = true
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+	for _, k := range sortedKeys {
+		if oldCmd.EnvUpdates[k] != newCmd.EnvUpdates[k] {
+			diffs = append(diffs, wrapperDifference{Kind: wrapperDifferenceEnv, Old: k + "=" + oldCmd.EnvUpdates[k], New: k + "=" + newCmd.EnvUpdates[k]})
+		}
+	}
+
+	return diffs
+}
+
+// renderWrapperDifferences concatenates diffs into the single human-readable
+// error string compareToOldWrapper has always returned.
+func renderWrapperDifferences(diffs []wrapperDifference) string {
+	lines := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		lines = append(lines, fmt.Sprintf("%s differs at index %d: old=%q new=%q", d.Kind, d.Index, d.Old, d.New))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// maybeEmitWrapperDifferencesJSON writes diffs to e's stderr as JSON when
+// COMPARE_WRAPPER_JSON is set, for migration dashboards that want
+// structured data instead of the concatenated string. It's a no-op
+// otherwise, and errors are swallowed since this is a diagnostics aid.
+func maybeEmitWrapperDifferencesJSON(e env, diffs []wrapperDifference) {
+	if _, ok := e.getenv(compareWrapperJSONEnvVar); !ok {
+		return
+	}
+	data, err := json.Marshal(diffs)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(e.stderr(), string(data))
+}
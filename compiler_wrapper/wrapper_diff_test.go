@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareCommandsPathDiff(t *testing.T) {
+	old := &command{Path: "/usr/bin/gcc"}
+	new_ := &command{Path: "/usr/bin/clang"}
+
+	diffs := compareCommands(old, new_)
+	if len(diffs) != 1 || diffs[0].Kind != wrapperDifferencePath {
+		t.Fatalf("got %v, want a single path diff", diffs)
+	}
+	if diffs[0].Old != "/usr/bin/gcc" || diffs[0].New != "/usr/bin/clang" {
+		t.Errorf("got %+v, want old=/usr/bin/gcc new=/usr/bin/clang", diffs[0])
+	}
+}
+
+func TestCompareCommandsArgDiff(t *testing.T) {
+	old := &command{Args: []string{"-c", "foo.c"}}
+	new_ := &command{Args: []string{"-c", "foo.c", "-target", "arm"}}
+
+	diffs := compareCommands(old, new_)
+	if len(diffs) != 2 {
+		t.Fatalf("got %v, want 2 diffs", diffs)
+	}
+	for _, d := range diffs {
+		if d.Kind != wrapperDifferenceArg {
+			t.Errorf("got kind %q, want arg", d.Kind)
+		}
+	}
+}
+
+func TestCompareCommandsEnvDiff(t *testing.T) {
+	old := &command{EnvUpdates: map[string]string{"FOO": "1"}}
+	new_ := &command{EnvUpdates: map[string]string{"FOO": "2"}}
+
+	diffs := compareCommands(old, new_)
+	if len(diffs) != 1 || diffs[0].Kind != wrapperDifferenceEnv {
+		t.Fatalf("got %v, want a single env diff", diffs)
+	}
+}
+
+func TestCompareCommandsNoDiff(t *testing.T) {
+	old := &command{Path: "/usr/bin/gcc", Args: []string{"-c", "foo.c"}}
+	new_ := &command{Path: "/usr/bin/gcc", Args: []string{"-c", "foo.c"}}
+
+	if diffs := compareCommands(old, new_); len(diffs) != 0 {
+		t.Errorf("expected no diffs, got %v", diffs)
+	}
+}
+
+func TestMaybeEmitWrapperDifferencesJSONEnabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{compareWrapperJSONEnvVar: "1"})
+	diffs := []wrapperDifference{{Kind: wrapperDifferencePath, Old: "a", New: "b"}}
+
+	maybeEmitWrapperDifferencesJSON(e, diffs)
+
+	got := e.stderrBuf.String()
+	if !strings.Contains(got, `"kind":"path"`) {
+		t.Errorf("expected JSON output, got %q", got)
+	}
+}
+
+func TestMaybeEmitWrapperDifferencesJSONDisabled(t *testing.T) {
+	e := newFakeEnv(map[string]string{})
+	diffs := []wrapperDifference{{Kind: wrapperDifferencePath, Old: "a", New: "b"}}
+
+	maybeEmitWrapperDifferencesJSON(e, diffs)
+
+	if e.stderrBuf.Len() != 0 {
+		t.Errorf("expected no output when disabled, got %q", e.stderrBuf.String())
+	}
+}